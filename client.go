@@ -7,65 +7,129 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/adobaai/paypal/internal/operation"
 )
 
 type Client struct {
 	base, id, secret string
+	basePath         string // The path of base, e.g. of a proxy
 
-	t  *Token
-	hc *http.Client
+	tmu sync.Mutex // Guards t
+	t   *Token
+	hc  *http.Client
+	m   *clientMetrics
+	log atomic.Pointer[clientLogger]
 }
 
+// NewClient returns a new client of the PayPal API at the base URL.
+//
+// The requests are traced and measured with the global OpenTelemetry providers,
+// see [otel.SetTracerProvider] and [otel.SetMeterProvider].
 func NewClient(base, id, secret string) *Client {
-	return &Client{
+	c := &Client{
 		base:   base,
 		id:     id,
 		secret: secret,
-		hc: &http.Client{
-			Transport: otelhttp.NewTransport(
-				http.DefaultTransport,
-				otelhttp.WithSpanNameFormatter(formatSpanName),
-				otelhttp.WithSpanOptions(
-					trace.WithAttributes(semconv.PeerServiceKey.String("paypal")),
-				),
+		m:      newClientMetrics(otel.GetMeterProvider()),
+	}
+	if u, err := url.Parse(base); err == nil {
+		c.basePath = u.Path
+	}
+	c.hc = &http.Client{Transport: c.newTransport(http.DefaultTransport)}
+	return c
+}
+
+func (c *Client) newTransport(rt http.RoundTripper) http.RoundTripper {
+	return &basePathTransport{
+		next: otelhttp.NewTransport(
+			&instrumentedTransport{next: &loggingTransport{next: rt, c: c}, metrics: c.m},
+			otelhttp.WithSpanNameFormatter(formatSpanName),
+			otelhttp.WithSpanOptions(
+				trace.WithAttributes(semconv.PeerServiceKey.String("paypal")),
 			),
-		},
+		),
+		path: c.basePath,
+	}
+}
+
+// basePathTransport puts the path of the base URL into the request context,
+// so that the request paths are matched with the operations after the path is stripped.
+type basePathTransport struct {
+	next http.RoundTripper
+	path string
+}
+
+func (t *basePathTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.path == "" {
+		return t.next.RoundTrip(req)
 	}
+	return t.next.RoundTrip(req.WithContext(operation.WithBasePath(req.Context(), t.path)))
+}
+
+// SetTransport sets the underlying transport of the HTTP client, e.g. for testing,
+// the requests are still traced, measured and logged.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.hc.Transport = c.newTransport(rt)
 }
 
 func formatSpanName(_ string, r *http.Request) string {
-	op := GetOperation(r.Context())
-	if op == "" {
+	op, ok := operation.FromRequest(r)
+	if !ok {
 		// Fallback to the default name
-		op = r.Method
+		return "PayPal " + r.Method
 	}
-	return "PayPal " + op
+	return "PayPal " + op.Name
 }
 
-type operationKey struct{}
+// Operation is a PayPal API operation of the client,
+// with the HTTP method and the path template, e.g. "/v2/checkout/orders/{order_id}".
+type Operation = operation.Operation
+
+// Operations returns the operations of the client.
+func Operations() []*Operation {
+	return operation.All()
+}
+
+// LookupOperation returns the operation of the client by name, e.g. "CreateOrder".
+func LookupOperation(name string) (*Operation, bool) {
+	return operation.Lookup(name)
+}
 
+// WithOperation returns a copy of the context with the operation name,
+// which names the spans, the metrics and the logs of the requests with the context.
 func WithOperation(ctx context.Context, op string) context.Context {
-	return context.WithValue(ctx, operationKey{}, op)
+	return operation.With(ctx, op)
 }
 
+// GetOperation returns the operation name in the context, or "" if there is none.
 func GetOperation(ctx context.Context) string {
-	return ctx.Value(operationKey{}).(string)
+	op, _ := operation.From(ctx)
+	return op
 }
 
 type Token struct {
-	Scope       string `json:"scope"`
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	AppID       string `json:"app_id"`
-	Nonce       string `json:"nonce"`
-	ExpiresIn   int    `json:"expires_in"`
-	expiresAt   time.Time
+	Scope        string `json:"scope"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // For authorization code grants
+	IDToken      string `json:"id_token,omitempty"`
+	ClientToken  string `json:"client_token,omitempty"` // See [Client.GenerateClientToken]
+	TokenType    string `json:"token_type"`
+	AppID        string `json:"app_id"`
+	Nonce        string `json:"nonce"`
+	ExpiresIn    int    `json:"expires_in"`
+	expiresAt    time.Time
 }
 
 func (t *Token) Valid() bool {
@@ -75,31 +139,68 @@ func (t *Token) Valid() bool {
 	return t.expiresAt.After(time.Now())
 }
 
+// ExpiresAt returns the time when the token expires,
+// which is a little earlier than the exact expiration time to tolerate network latency.
+func (t *Token) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// setExpiresAt sets the expiration time of the token requested at start.
+func (t *Token) setExpiresAt(start time.Time) {
+	// Minus 2 seconds is to prevent expiration due to network latency.
+	t.expiresAt = start.Add(time.Duration(t.ExpiresIn-2) * time.Second)
+}
+
 // Auth requests a new token from PayPal server.
 // See https://developer.paypal.com/api/rest/authentication/.
 func (c *Client) Auth(ctx context.Context) (res *Token, err error) {
 	ctx = WithOperation(ctx, "Auth")
+	return c.requestToken(ctx, url.Values{"grant_type": {"client_credentials"}})
+}
+
+// requestToken requests a token with the form as the request body.
+func (c *Client) requestToken(ctx context.Context, form url.Values) (res *Token, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		c.base+"/v1/oauth2/token", strings.NewReader("grant_type=client_credentials"))
+		c.base+"/v1/oauth2/token", strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("new request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBasicAuth(c.id, c.secret)
 
 	start := time.Now()
 	if res, err = doJSON[Token](ctx, c, req); err != nil {
 		return
 	}
-
-	// Minus 2 seconds is to prevent expiration due to network latency.
-	res.expiresAt = start.Add(time.Duration(res.ExpiresIn-2) * time.Second)
+	res.setExpiresAt(start)
 	return
 }
 
-func (c *Client) checkToken(ctx context.Context) (err error) {
-	if !c.t.Valid() {
-		c.t, err = c.Auth(ctx)
+// accessToken returns the client's access token,
+// which is refreshed if it is missing, expired or the rejected one.
+// The concurrent requests wait for a single refresh,
+// and the ones rejected with the same token do not refresh it again.
+func (c *Client) accessToken(ctx context.Context, rejected string) (res string, err error) {
+	c.tmu.Lock()
+	defer c.tmu.Unlock()
+	switch {
+	case c.t == nil:
+		err = c.refreshToken(ctx, reasonMissing)
+	case !c.t.Valid():
+		err = c.refreshToken(ctx, reasonExpired)
+	case rejected != "" && c.t.AccessToken == rejected:
+		err = c.refreshToken(ctx, reasonRejected)
+	}
+	if err != nil {
+		return
 	}
+	return c.t.AccessToken, nil
+}
+
+// refreshToken requests a new access token for the reason, c.tmu must be held.
+func (c *Client) refreshToken(ctx context.Context, reason string) (err error) {
+	c.m.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attrReason.String(reason)))
+	c.t, err = c.Auth(ctx)
 	return
 }
 
@@ -113,11 +214,11 @@ func JSON[R any](ctx context.Context, c *Client, method, path string, data any,
 	if err != nil {
 		return nil, fmt.Errorf("new request: %w", err)
 	}
-	if err = c.checkToken(ctx); err != nil {
+	hres, err := c.doAuth(ctx, req)
+	if err != nil {
 		return
 	}
-	req.Header.Set("Authorization", "Bearer "+c.t.AccessToken)
-	return doJSON[R](ctx, c, req)
+	return parseResp[R](hres)
 }
 
 // JSONNop is similar to [JSON] but with the response body discarded.
@@ -127,29 +228,46 @@ func JSONNop(ctx context.Context, c *Client, method, path string, data any) (err
 	if err != nil {
 		return fmt.Errorf("new request: %w", err)
 	}
-	if err = c.checkToken(ctx); err != nil {
-		return
-	}
-	req.Header.Set("Authorization", "Bearer "+c.t.AccessToken)
-	hres, err := c.hc.Do(req)
+	hres, err := c.doAuth(ctx, req)
 	if err != nil {
-		return fmt.Errorf("do: %w", err)
+		return
 	}
 	defer hres.Body.Close()
 	if hres.StatusCode < 400 {
 		return nil
 	}
-	if hres.ContentLength == 0 {
-		return &Error{
-			StatusCode: hres.StatusCode,
+	return respError(hres)
+}
+
+// doAuth performs the request with the client's access token.
+// If the token is rejected, e.g. revoked or expired earlier than expected,
+// the request is retried once with a new token.
+func (c *Client) doAuth(ctx context.Context, req *http.Request) (res *http.Response, err error) {
+	var token string
+	for attempt := 1; ; attempt++ {
+		if token, err = c.accessToken(ctx, token); err != nil {
+			return
 		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		if res, err = c.hc.Do(req); err != nil {
+			return nil, fmt.Errorf("do: %w", err)
+		}
+		if res.StatusCode != http.StatusUnauthorized || attempt > 1 ||
+			req.Body != nil && req.GetBody == nil {
+			return
+		}
+
+		res.Body.Close()
+		c.m.retries.Add(ctx, 1, metric.WithAttributes(
+			attrOperation.String(GetOperation(ctx)), attrReason.String(reasonRejected)))
+		retry := req.Clone(withRetry(req.Context(), attempt+1, reasonRejected))
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("get body: %w", err)
+			}
+		}
+		req = retry
 	}
-	e, err := RespJSON[Error](hres)
-	if err != nil {
-		return fmt.Errorf("unmarshal error: %w", err)
-	}
-	e.StatusCode = hres.StatusCode
-	return e
 }
 
 func doJSON[R any](ctx context.Context, c *Client, req *http.Request) (res *R, err error) {
@@ -157,16 +275,40 @@ func doJSON[R any](ctx context.Context, c *Client, req *http.Request) (res *R, e
 	if err != nil {
 		return nil, fmt.Errorf("do: %w", err)
 	}
+	return parseResp[R](hres)
+}
 
+// parseResp unmarshals the response body into a new R,
+// or into an [Error] if the status code indicates an error.
+func parseResp[R any](hres *http.Response) (res *R, err error) {
 	if hres.StatusCode < 400 {
 		return RespJSON[R](hres)
 	}
-	e, err := RespJSON[Error](hres)
+	return nil, respError(hres)
+}
+
+// maxErrorBodyBytes limits the body kept as the message of an [Error] which is not JSON.
+const maxErrorBodyBytes = 512
+
+// respError returns the [Error] of the response with an error status code
+// and then closes the body.
+// The body which is not a PayPal error, e.g. an HTML page of a gateway or an empty one,
+// is kept as the message, truncated to maxErrorBodyBytes.
+func respError(hres *http.Response) error {
+	defer hres.Body.Close()
+	bs, err := io.ReadAll(hres.Body)
 	if err != nil {
-		return nil, fmt.Errorf("unmarshal error: %w", err)
+		return fmt.Errorf("read error: %w", err)
+	}
+	e := new(Error)
+	if err = json.Unmarshal(bs, e); err != nil {
+		if len(bs) > maxErrorBodyBytes {
+			bs = bs[:maxErrorBodyBytes]
+		}
+		e = &Error{Message: strings.TrimSpace(string(bs))}
 	}
 	e.StatusCode = hres.StatusCode
-	return nil, e
+	return e
 }
 
 // Error is the PayPal API error response.
@@ -189,9 +331,26 @@ func (e *Error) Error() string {
 	if e.Err != "" {
 		return e.Err + ": " + e.ErrDesc
 	}
+	if e.Name == "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
 	return fmt.Sprintf("%s: %s (%s)", e.Name, e.Message, e.DebugID)
 }
 
+// HasIssue reports whether the error has the issue, e.g. "ORDER_NOT_APPROVED",
+// in its details, or is of the name, e.g. "RESOURCE_NOT_FOUND".
+func (e *Error) HasIssue(issue string) bool {
+	if e.Name == issue || e.Err == issue {
+		return true
+	}
+	for _, d := range e.Details {
+		if d.Issue == issue {
+			return true
+		}
+	}
+	return false
+}
+
 type ErrorDetail struct {
 	Field       string `json:"field"`
 	Value       string `json:"value"`
@@ -208,6 +367,16 @@ type Link struct {
 	Method string `json:"method"`
 }
 
+// findLink returns the href of the first link with the given rel, or "" if not found.
+func findLink(links []*Link, rel string) string {
+	for _, l := range links {
+		if l.Rel == rel {
+			return l.HRef
+		}
+	}
+	return ""
+}
+
 // NewJSONRequest returns a new [http.Request] with the given data marshaled to JSON format.
 func NewJSONRequest(ctx context.Context, method, url string, data any,
 ) (res *http.Request, err error) {
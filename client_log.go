@@ -0,0 +1,157 @@
+package paypal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/adobaai/paypal/internal/operation"
+)
+
+// defaultMaxBodyBytes limits the logged bodies by default.
+const defaultMaxBodyBytes = 8 << 10
+
+// LogOptions are the options of [Client.SetLogger].
+type LogOptions struct {
+	// Bodies logs the JSON and the form bodies of the requests and the responses,
+	// with the values redacted by Redact.
+	Bodies bool
+	// Redact is the redaction policy of the bodies, defaults to [DefaultRedactPaths].
+	Redact *RedactPolicy
+	// MaxBodyBytes limits the size of the logged bodies, defaults to 8 KiB.
+	// The longer bodies are logged as their sizes only.
+	MaxBodyBytes int
+}
+
+var defaultRedactPolicy = MustRedactPolicy(DefaultRedactPaths...)
+
+type clientLogger struct {
+	l    *slog.Logger
+	opts LogOptions
+}
+
+// SetLogger logs each request to PayPal with the operation, the route, the status code,
+// the latency and the PayPal debug ID, see [LogOptions] for the bodies.
+// The headers, and so the credentials, are never logged.
+// A nil l disables the logging.
+// It is safe to call while the client is in use.
+func (c *Client) SetLogger(l *slog.Logger, opts *LogOptions) {
+	if l == nil {
+		c.log.Store(nil)
+		return
+	}
+	cl := &clientLogger{l: l}
+	if opts != nil {
+		cl.opts = *opts
+	}
+	if cl.opts.Redact == nil {
+		cl.opts.Redact = defaultRedactPolicy
+	}
+	if cl.opts.MaxBodyBytes <= 0 {
+		cl.opts.MaxBodyBytes = defaultMaxBodyBytes
+	}
+	c.log.Store(cl)
+}
+
+// loggingTransport logs the requests with the logger of the client at the time of the request.
+type loggingTransport struct {
+	next http.RoundTripper
+	c    *Client
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cl := t.c.log.Load()
+	if cl == nil {
+		return t.next.RoundTrip(req)
+	}
+	ctx := req.Context()
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+	if op, ok := operation.FromRequest(req); ok {
+		attrs = append(attrs, slog.String("operation", op.Name))
+		if op.Path != "" {
+			attrs = append(attrs, slog.String("route", op.Path))
+		}
+	}
+	if ri, ok := ctx.Value(retryKey{}).(*retryInfo); ok {
+		attrs = append(attrs, slog.Int("attempt", ri.attempt))
+	}
+	if cl.opts.Bodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			bs, _ := io.ReadAll(body)
+			body.Close()
+			attrs = append(attrs, cl.body("request_body", req.Header.Get("Content-Type"), bs))
+		}
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+	if err != nil {
+		cl.l.ErrorContext(ctx, "PayPal request failed", append(attrs, slog.Any("error", err))...)
+		return res, err
+	}
+
+	attrs = append(attrs, slog.Int("status", res.StatusCode))
+	if id := res.Header.Get("Paypal-Debug-Id"); id != "" {
+		attrs = append(attrs, slog.String("debug_id", id))
+	}
+	if cl.opts.Bodies {
+		bs, err := peekBody(res)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, cl.body("response_body", res.Header.Get("Content-Type"), bs))
+	}
+	switch {
+	case res.StatusCode >= 500:
+		cl.l.ErrorContext(ctx, "PayPal request", attrs...)
+	case res.StatusCode >= 400:
+		cl.l.WarnContext(ctx, "PayPal request", attrs...)
+	default:
+		cl.l.InfoContext(ctx, "PayPal request", attrs...)
+	}
+	return res, nil
+}
+
+// body returns the attribute of the redacted body,
+// or of its size if it is too long or neither JSON nor a form.
+func (cl *clientLogger) body(key, contentType string, bs []byte) slog.Attr {
+	if len(bs) == 0 {
+		return slog.String(key, "")
+	}
+	var v any
+	ok := false
+	if len(bs) <= cl.opts.MaxBodyBytes {
+		switch {
+		case strings.HasPrefix(contentType, "application/json"):
+			ok = json.Unmarshal(bs, &v) == nil
+		case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+			var form url.Values
+			if form, ok = parseForm(bs); ok {
+				m := map[string]any{}
+				for k, vs := range form {
+					m[k] = vs[0]
+				}
+				v = m
+			}
+		}
+	}
+	if !ok {
+		return slog.Group(key, slog.Int("bytes", len(bs)))
+	}
+	return slog.Any(key, cl.opts.Redact.Redact(v))
+}
+
+func parseForm(bs []byte) (url.Values, bool) {
+	form, err := url.ParseQuery(string(bytes.TrimSpace(bs)))
+	return form, err == nil
+}
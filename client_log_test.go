@@ -0,0 +1,134 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestRedactPolicy(t *testing.T) {
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "UNPROCESSABLE_ENTITY",
+		"payer": {"name": {"given_name": "John"}, "email_address": "john@example.com"},
+		"purchase_units": [
+			{"shipping": {"name": {"full_name": "John Doe"}}, "items": [{"name": "Tea", "sku": "T1"}]}
+		],
+		"payment_source": {"card": {"number": "4111111111111111", "name": "John Doe", "brand": "VISA"}}
+	}`), &v))
+
+	p := MustRedactPolicy(DefaultRedactPaths...)
+	v = p.Redact(v)
+	ptesting.R(v, nil).NoError(t).JSONEq(`{
+		"name": "UNPROCESSABLE_ENTITY",
+		"payer": {"name": "REDACTED", "email_address": "REDACTED"},
+		"purchase_units": [
+			{"shipping": {"name": "REDACTED"}, "items": [{"name": "Tea", "sku": "T1"}]}
+		],
+		"payment_source": {"card": {"number": "REDACTED", "name": "REDACTED", "brand": "VISA"}}
+	}`)
+
+	p = MustRedactPolicy("$.purchase_units[*].items[*].*", "$.payment_source.card")
+	v = p.Redact(v)
+	ptesting.R(v, nil).NoError(t).JSONEq(`{
+		"name": "UNPROCESSABLE_ENTITY",
+		"payer": {"name": "REDACTED", "email_address": "REDACTED"},
+		"purchase_units": [
+			{"shipping": {"name": "REDACTED"}, "items": [{"name": "REDACTED", "sku": "REDACTED"}]}
+		],
+		"payment_source": {"card": "REDACTED"}
+	}`)
+
+	for _, p := range []string{"", "$", "payer", "$payer", "$.", "$..", "$.payer[0]", "$.a..b."} {
+		_, err := NewRedactPolicy(p)
+		assert.Error(t, err, p)
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	ctx := context.Background()
+	s := ptesting.NewServer()
+	defer s.Close()
+	c := NewClient(s.URL, "id", "secret")
+	var buf bytes.Buffer
+	redact := MustRedactPolicy(append([]string{"$.purchase_units[*].description"}, DefaultRedactPaths...)...)
+	c.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)), &LogOptions{Bodies: true, Redact: redact})
+
+	order := ptesting.R(c.CreateOrder(ctx, &CreateOrderReq{Order: &Order{
+		Intent: OICapture,
+		PurchaseUnits: []*PurchaseUnit{{
+			Amount:      &Amount{CurrencyCode: "USD", Value: "1.00"},
+			Description: "Gift for John Doe",
+		}},
+	}})).NoError(t).V()
+	_, err := c.CaptureOrder(ctx, &CaptureOrderReq{ID: order.ID})
+	require.Error(t, err)
+
+	out := buf.String()
+	for _, secret := range []string{"secret", "aWQ6c2VjcmV0", c.t.AccessToken, "John Doe"} {
+		assert.NotContains(t, out, secret)
+	}
+
+	var logs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		logs = append(logs, m)
+	}
+	require.Len(t, logs, 3)
+	assert.Equal(t, "Auth", logs[0]["operation"])
+	assert.Equal(t, map[string]any{"grant_type": "client_credentials"}, logs[0]["request_body"])
+	assert.Equal(t, RedactedValue, logs[0]["response_body"].(map[string]any)["access_token"])
+
+	assert.Equal(t, "INFO", logs[1]["level"])
+	assert.Equal(t, "CreateOrder", logs[1]["operation"])
+	assert.Equal(t, "/v2/checkout/orders", logs[1]["path"])
+	assert.Equal(t, 201.0, logs[1]["status"])
+	assert.NotEmpty(t, logs[1]["debug_id"])
+	assert.Contains(t, logs[1], "duration")
+
+	assert.Equal(t, "WARN", logs[2]["level"])
+	assert.Equal(t, "CaptureOrder", logs[2]["operation"])
+	assert.Equal(t, "/v2/checkout/orders/{order_id}/capture", logs[2]["route"])
+	assert.Equal(t, 422.0, logs[2]["status"])
+	assert.Equal(t, "UNPROCESSABLE_ENTITY", logs[2]["response_body"].(map[string]any)["name"])
+
+	buf.Reset()
+	c.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)), nil)
+	ptesting.R(c.GetOrder(ctx, &GetOrderReq{ID: order.ID})).NoError(t)
+	assert.NotContains(t, buf.String(), "response_body")
+	assert.Contains(t, buf.String(), `"operation":"GetOrder"`)
+
+	buf.Reset()
+	c.SetLogger(nil, nil)
+	ptesting.R(c.GetOrder(ctx, &GetOrderReq{ID: order.ID})).NoError(t)
+	assert.Zero(t, buf.Len())
+
+	// The logger is swapped while the requests are in flight
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := c.GetOrder(ctx, &GetOrderReq{ID: order.ID})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		c.SetLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)), nil)
+		c.SetLogger(nil, nil)
+	}
+	wg.Wait()
+}
@@ -2,12 +2,18 @@ package paypal
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/adobaai/paypal/internal/operation"
 	"github.com/adobaai/paypal/ptesting"
 )
 
@@ -19,9 +25,48 @@ func NewTestClient() *Client {
 	)
 }
 
+// newTestClient returns a client of the sandbox if UT_PAYPAL_ID is set,
+// or else a client of a fake server, which is returned as well and closed after the test.
+func newTestClient(t *testing.T) (c *Client, fake *ptesting.Server) {
+	if os.Getenv("UT_PAYPAL_ID") != "" {
+		return NewTestClient(), nil
+	}
+	fake = ptesting.NewServer()
+	t.Cleanup(fake.Close)
+	fake.ClientID, fake.ClientSecret = "id", "secret"
+	return NewClient(fake.URL, "id", "secret"), fake
+}
+
+// NewMockClient returns a client talking to a local server
+// which issues tokens and serves the other requests with the given handler.
+func NewMockClient(t *testing.T, h http.Handler) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"A21AA","token_type":"Bearer","expires_in":32400}`))
+	})
+	mux.Handle("/", h)
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return NewClient(s.URL, "id", "secret")
+}
+
+// ServeJSON returns a handler which checks the request method and path,
+// and then writes the given status and body.
+func ServeJSON(t *testing.T, method, path string, status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, method, r.Method)
+		assert.Equal(t, path, r.URL.RequestURI())
+		assert.Equal(t, "Bearer A21AA", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
 func TestAuth(t *testing.T) {
 	ctx := context.Background()
-	c := NewTestClient()
+	c, _ := newTestClient(t)
 	token := ptesting.R(c.Auth(ctx)).NoError(t).V()
 	assert.NotZero(t, token.Scope)
 	assert.NotZero(t, token.AccessToken)
@@ -33,13 +78,13 @@ func TestAuth(t *testing.T) {
 
 func TestError(t *testing.T) {
 	ctx := context.Background()
+	c, _ := newTestClient(t)
 	t.Run("Auth", func(t *testing.T) {
-		c := NewClient("https://api-m.sandbox.paypal.com", "1234", "5678")
+		c := NewClient(c.base, "1234", "5678")
 		ptesting.R(c.Auth(ctx)).EqualError(t, "invalid_client: Client Authentication failed")
 	})
 
 	t.Run("CreateOrder", func(t *testing.T) {
-		c := NewTestClient()
 		var e *Error
 		order := &Order{
 			Intent: OICapture,
@@ -79,13 +124,137 @@ type HelloResp struct {
 }
 
 func TestJSON(t *testing.T) {
+	// Echoes the JSON body as httpbin.org/anything does
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v any
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"json": v})
+	}))
+	defer s.Close()
 	c := &http.Client{}
 	ctx := context.Background()
 	name := "Фёдор Миха́йлович Достое́вский"
-	url := "https://httpbin.org/anything"
+	url := s.URL + "/anything"
 	hreq := ptesting.R(NewJSONRequest(ctx, http.MethodPost, url, Hello{Name: name})).NoError(t).V()
 	hres := ptesting.R(c.Do(hreq)).NoError(t).V()
 	ptesting.R(RespJSON[HelloResp](hres)).NoError(t).Do(func(t *testing.T, it *HelloResp) {
 		assert.Equal(t, name, it.JSON.Name)
 	})
 }
+
+func TestOperations(t *testing.T) {
+	// Each API method of the client is an operation in the catalog
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	ct := reflect.TypeOf(&Client{})
+	names := map[string]bool{}
+	for i := 0; i < ct.NumMethod(); i++ {
+		m := ct.Method(i)
+		if m.Type.NumIn() > 1 && m.Type.In(1) == ctxType {
+			names[m.Name] = true
+			_, ok := LookupOperation(m.Name)
+			assert.True(t, ok, m.Name)
+		}
+	}
+	ops := Operations()
+	assert.Len(t, ops, len(names))
+	ops[0].Name = "Changed"
+	_, ok := LookupOperation("Changed")
+	assert.False(t, ok)
+
+	op, ok := LookupOperation("CaptureOrder")
+	assert.True(t, ok)
+	assert.Equal(t, &Operation{
+		Name: "CaptureOrder", Method: http.MethodPost, Path: "/v2/checkout/orders/{order_id}/capture",
+	}, op)
+	params, ok := op.Match("/v2/checkout/orders/5O190127TN364715T/capture")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"order_id": "5O190127TN364715T"}, params)
+	_, ok = op.Match("/v2/checkout/orders//capture")
+	assert.False(t, ok)
+}
+
+func TestOperationRequests(t *testing.T) {
+	// Each API method of the client requests the method and the path of its catalog entry,
+	// also behind a proxy with a base path
+	ctx := context.Background()
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	var got []*http.Request
+	c := NewClient("https://proxy.example.com/paypal", "id", "secret")
+	c.SetTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"A21AA","expires_in":32400}`)),
+		}, nil
+	}))
+	cv := reflect.ValueOf(c)
+	for _, op := range Operations() {
+		m := cv.MethodByName(op.Name)
+		if !assert.True(t, m.IsValid(), op.Name) || m.Type().In(0) != ctxType {
+			continue
+		}
+		args := []reflect.Value{reflect.ValueOf(WithOperation(ctx, "Wrong"))}
+		if m.Type().NumIn() > 1 {
+			// The request with the string fields set, e.g. the IDs in the paths,
+			// and the struct pointer fields, e.g. the token of GetUserInfo
+			args = append(args, newFilled(m.Type().In(1).Elem(), 2))
+		}
+		got = got[:0]
+		m.Call(args)
+		if !assert.NotEmpty(t, got, op.Name) {
+			continue
+		}
+		req := got[len(got)-1]
+		assert.Equal(t, op.Name, GetOperation(req.Context()), op.Name)
+		assert.Equal(t, op.Method, req.Method, op.Name)
+		assert.True(t, strings.HasPrefix(req.URL.Path, "/paypal/"), req.URL.Path)
+		_, ok := op.Match(operation.Path(req))
+		assert.True(t, ok, "%s: %s", op.Name, req.URL.Path)
+
+		// Matched by the path without the name
+		req = req.WithContext(operation.WithBasePath(ctx, "/paypal"))
+		matched, ok := operation.FromRequest(req)
+		if assert.True(t, ok, op.Name) && op.Path != "/v1/oauth2/token" {
+			assert.Equal(t, op.Name, matched.Name)
+		}
+	}
+}
+
+// newFilled returns a new pointer to the struct type with the string fields set to "X",
+// and the struct pointer fields filled to the depth.
+func newFilled(typ reflect.Type, depth int) reflect.Value {
+	v := reflect.New(typ)
+	for i := 0; i < typ.NumField(); i++ {
+		switch f := v.Elem().Field(i); {
+		case !f.CanSet():
+		case f.Kind() == reflect.String:
+			f.SetString("X")
+		case depth > 1 && f.Kind() == reflect.Pointer && f.Type().Elem().Kind() == reflect.Struct:
+			f.Set(newFilled(f.Type().Elem(), depth-1))
+		}
+	}
+	return v
+}
+
+func TestGetOperation(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", GetOperation(ctx))
+	assert.Equal(t, "CreateOrder", GetOperation(WithOperation(ctx, "CreateOrder")))
+
+	// Requests built without the client are named by the catalog
+	for url, name := range map[string]string{
+		"https://api-m.paypal.com/v2/checkout/orders/5O190127TN364715T":        "PayPal GetOrder",
+		"https://api-m.paypal.com/v2/checkout/orders/5O190127TN364715T/refund": "PayPal GET",
+	} {
+		req := ptesting.R(NewJSONRequest(ctx, http.MethodGet, url, nil)).NoError(t).V()
+		assert.Equal(t, name, formatSpanName("", req))
+	}
+	req := ptesting.R(NewJSONRequest(WithOperation(ctx, "Custom"), http.MethodGet,
+		"https://api-m.paypal.com/v1/custom", nil)).NoError(t).V()
+	assert.Equal(t, "PayPal Custom", formatSpanName("", req))
+}
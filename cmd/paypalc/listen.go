@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/adobaai/paypal"
+	"github.com/adobaai/paypal/internal/webhook"
+)
+
+func listenCmd(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("webhook listen", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "Address to listen on")
+	verify := fs.String("verify", "cert", "How to verify the signatures: cert, api or none. "+
+		"The api verification uses the credentials in PAYPAL_ID and PAYPAL_SECRET")
+	base := fs.String("base", "https://api-m.sandbox.paypal.com", "PayPal API base URL for the api verification")
+	webhookID := fs.String("webhook-id", "", "ID of the webhook in the Developer Portal")
+	target := fs.String("target", "", "URL to forward the webhooks to with the original headers")
+	store := fs.String("store", "", "Path of the JSONL webhook store to record the webhooks")
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	var v paypal.WebhookVerifier
+	switch *verify {
+	case "cert":
+		v = paypal.NewCertVerifier()
+	case "api":
+		v = paypal.NewClient(*base, os.Getenv("PAYPAL_ID"), os.Getenv("PAYPAL_SECRET"))
+	case "none":
+		v = paypal.WebhookVerifierFunc(func(context.Context, *paypal.VerifyWSReq) (bool, error) {
+			return true, nil
+		})
+	default:
+		return fmt.Errorf("unknown verify: %q", *verify)
+	}
+
+	l := newListener(v, *webhookID, *target, os.Stdout)
+	if *store != "" {
+		s, err := paypal.OpenFileStore(*store)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		l.h.Store = s
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	srv := &http.Server{Addr: *addr, Handler: l}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	fmt.Printf("Listening on %s\n", *addr)
+	if err = srv.ListenAndServe(); errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return
+}
+
+// listener verifies the webhooks, prints them and forwards them to the target.
+type listener struct {
+	h      *paypal.WebhookHandler
+	target string
+	out    io.Writer
+}
+
+func newListener(v paypal.WebhookVerifier, webhookID, target string, out io.Writer) *listener {
+	l := &listener{target: target, out: out}
+	l.h = paypal.NewWebhookHandler(v, webhookID)
+	l.h.MaxBodyBytes = paypal.DefaultMaxWebhookBytes
+	l.h.HandleDefault(l.handle)
+	return l
+}
+
+type rawRequestKey struct{}
+
+type rawRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func (l *listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, l.h.MaxBodyBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > l.h.MaxBodyBytes {
+		// Rejected here rather than truncated into a signature failure
+		l.rejected(http.StatusRequestEntityTooLarge)
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	ctx := context.WithValue(r.Context(), rawRequestKey{}, &rawRequest{r.Header.Clone(), body})
+	rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+	l.h.ServeHTTP(rec, r.WithContext(ctx))
+	if rec.code != http.StatusOK {
+		l.rejected(rec.code)
+	}
+}
+
+func (l *listener) rejected(code int) {
+	fmt.Fprintf(l.out, "%s rejected: %d %s\n", time.Now().Format(time.RFC3339), code, http.StatusText(code))
+}
+
+func (l *listener) handle(ctx context.Context, wh *paypal.Webhook) error {
+	raw := ctx.Value(rawRequestKey{}).(*rawRequest)
+	fmt.Fprintf(l.out, "%s %s %s\n", time.Now().Format(time.RFC3339), wh.EventType, wh.ID)
+	if wh.Summary != "" {
+		fmt.Fprintf(l.out, "  %s\n", wh.Summary)
+	}
+	buf := new(bytes.Buffer)
+	if err := json.Indent(buf, raw.body, "  ", "  "); err == nil {
+		fmt.Fprintf(l.out, "  %s\n", buf)
+	}
+	if l.target == "" {
+		return nil
+	}
+	if err := forward(ctx, l.target, raw.header, raw.body); err != nil {
+		fmt.Fprintf(l.out, "  forward failed: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func simulateCmd(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("webhook simulate", flag.ContinueOnError)
+	target := fs.String("target", "http://localhost:8080", "URL to post the simulated webhook to")
+	event := fs.String("event", "", "Event type, e.g. PAYMENT.CAPTURE.COMPLETED")
+	list := fs.Bool("list", false, "List the known event types")
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+	if *list {
+		for _, et := range paypal.AllEventTypes() {
+			fmt.Printf("%-50s %s\n", et, et.Group())
+		}
+		return
+	}
+
+	et := paypal.EventType(*event)
+	if !et.Valid() {
+		return fmt.Errorf("unknown event type: %q, see -list", *event)
+	}
+	header, body, err := simulatedWebhook(et, time.Now())
+	if err != nil {
+		return
+	}
+	if err = forward(ctx, *target, header, body); err != nil {
+		return
+	}
+	fmt.Printf("%s posted to %s\n", et, *target)
+	return
+}
+
+// simulatedWebhook returns an unsigned sample webhook request of the event type,
+// in the shape of the events sent by the PayPal simulator.
+// It can only be verified with the "none" verification.
+func simulatedWebhook(et paypal.EventType, now time.Time) (header http.Header, body []byte, err error) {
+	version := "2.0"
+	if r, ok := webhook.LookupResource(string(et)); ok {
+		version = r.Version
+	}
+	wh := map[string]any{
+		"id":               "WH-SIM-" + randomID(),
+		"event_version":    "1.0",
+		"create_time":      now.UTC().Format(time.RFC3339),
+		"resource_type":    et.ResourceType(),
+		"event_type":       et,
+		"summary":          "Simulated " + strings.ToLower(string(et)) + " event",
+		"resource":         webhook.SampleResource(string(et), "SIM-"+randomID()),
+		"resource_version": version,
+	}
+	if body, err = json.Marshal(wh); err != nil {
+		return nil, nil, fmt.Errorf("marshal: %w", err)
+	}
+	header = http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(paypal.HeaderAuthAlgo, "SHA256withRSA")
+	header.Set(paypal.HeaderCertURL, "https://api.sandbox.paypal.com/v1/notifications/certs/SIMULATED")
+	header.Set(paypal.HeaderTransmissionID, randomID())
+	header.Set(paypal.HeaderTransmissionSig, "SIMULATED")
+	header.Set(paypal.HeaderTransmissionTime, now.UTC().Format(time.RFC3339))
+	return
+}
+
+func randomID() string {
+	bs := make([]byte, 8)
+	_, _ = rand.Read(bs)
+	return strings.ToUpper(hex.EncodeToString(bs))
+}
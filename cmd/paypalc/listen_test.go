@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal"
+)
+
+func Test_listener(t *testing.T) {
+	var forwarded []*http.Request
+	var bodies []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, _ := io.ReadAll(r.Body)
+		forwarded = append(forwarded, r)
+		bodies = append(bodies, string(bs))
+	}))
+	defer target.Close()
+
+	none := paypal.WebhookVerifierFunc(func(context.Context, *paypal.VerifyWSReq) (bool, error) {
+		return true, nil
+	})
+	out := new(bytes.Buffer)
+	srv := httptest.NewServer(newListener(none, "WEBHOOK-ID", target.URL, out))
+	defer srv.Close()
+
+	ctx := context.Background()
+	header, body, err := simulatedWebhook(paypal.PaymentCaptureCompleted, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, forward(ctx, srv.URL, header, body))
+
+	require.Len(t, forwarded, 1)
+	assert.Equal(t, string(body), bodies[0])
+	for _, k := range []string{paypal.HeaderTransmissionID, paypal.HeaderTransmissionTime, paypal.HeaderCertURL} {
+		assert.Equal(t, header.Get(k), forwarded[0].Header.Get(k), k)
+	}
+	wh := new(paypal.Webhook)
+	require.NoError(t, json.Unmarshal(body, wh))
+	assert.Equal(t, paypal.RTCapture, wh.ResourceType)
+	assert.Contains(t, out.String(), "PAYMENT.CAPTURE.COMPLETED "+wh.ID)
+	assert.Contains(t, out.String(), `"resource_type": "capture"`)
+
+	// Rejected by the verifier
+	invalid := paypal.WebhookVerifierFunc(func(context.Context, *paypal.VerifyWSReq) (bool, error) {
+		return false, nil
+	})
+	out.Reset()
+	srv2 := httptest.NewServer(newListener(invalid, "WEBHOOK-ID", target.URL, out))
+	defer srv2.Close()
+	assert.EqualError(t, forward(ctx, srv2.URL, header, body), "status 401")
+	assert.Contains(t, out.String(), "rejected: 401 Unauthorized")
+	assert.Len(t, forwarded, 1)
+
+	// Too large
+	out.Reset()
+	large := append(body[:len(body)-1:len(body)-1], `,"padding":"`+strings.Repeat("x", paypal.DefaultMaxWebhookBytes)+`"}`...)
+	assert.EqualError(t, forward(ctx, srv.URL, header, large), "status 413")
+	assert.Contains(t, out.String(), "rejected: 413 Request Entity Too Large")
+	assert.Len(t, forwarded, 1)
+}
+
+func Test_simulatedWebhook(t *testing.T) {
+	for _, et := range paypal.AllEventTypes() {
+		_, body, err := simulatedWebhook(et, time.Now())
+		require.NoError(t, err, et)
+		wh := new(paypal.Webhook)
+		require.NoError(t, json.Unmarshal(body, wh), et)
+		assert.Equal(t, et.ResourceType(), wh.ResourceType, et)
+		_, err = wh.Decode()
+		assert.NoError(t, err, et)
+	}
+
+	_, body, err := simulatedWebhook(paypal.PaymentCaptureDenied, time.Now())
+	require.NoError(t, err)
+	wh := new(paypal.Webhook)
+	require.NoError(t, json.Unmarshal(body, wh))
+	res, err := wh.Decode()
+	require.NoError(t, err)
+	capture := res.(*paypal.Capture)
+	assert.Equal(t, paypal.CaptureDeclined, capture.Status)
+	assert.Equal(t, "10.00", capture.Amount.Value)
+}
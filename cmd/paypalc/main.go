@@ -25,6 +25,9 @@ func main() {
 }
 
 func do(ctx context.Context) error {
+	if flag.Arg(0) == "webhook" {
+		return webhookCmd(ctx, flag.Args()[1:])
+	}
 	if *webhookEnum != "" {
 		return genPayPal(ctx)
 	}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobaai/paypal"
+)
+
+// webhookCmd runs the webhook subcommands.
+func webhookCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: paypalc webhook listen|simulate|replay [flags]")
+	}
+	switch args[0] {
+	case "listen":
+		return listenCmd(ctx, args[1:])
+	case "simulate":
+		return simulateCmd(ctx, args[1:])
+	case "replay":
+		return replayCmd(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown webhook command: %q", args[0])
+	}
+}
+
+func replayCmd(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("webhook replay", flag.ContinueOnError)
+	store := fs.String("store", "webhooks.jsonl", "Path of the JSONL webhook store")
+	target := fs.String("target", "", "URL of a development webhook handler without a paypal.ReplayGuard "+
+		"to post the webhooks to with the original headers, the webhooks are only printed if empty")
+	keepGoing := fs.Bool("continue-on-error", false, "Continue with the next webhook if one fails")
+	events := fs.String("event", "", "Comma-separated event type patterns, e.g. PAYMENT.CAPTURE.*")
+	since := fs.String("since", "", "Replay the webhooks created at or after the RFC 3339 time")
+	until := fs.String("until", "", "Replay the webhooks created before the RFC 3339 time")
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	filter := &paypal.ReplayFilter{}
+	if *events != "" {
+		filter.Patterns = strings.Split(*events, ",")
+	}
+	if filter.Since, err = parseTime(*since); err != nil {
+		return fmt.Errorf("since: %w", err)
+	}
+	if filter.Until, err = parseTime(*until); err != nil {
+		return fmt.Errorf("until: %w", err)
+	}
+
+	f, err := os.Open(*store)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+	rt := &replayTarget{URL: *target, ContinueOnError: *keepGoing}
+	n, err := replay(ctx, f, filter, rt, os.Stdout)
+	fmt.Printf("%d webhooks replayed\n", n)
+	return
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// replayTarget is where the webhooks are replayed to.
+type replayTarget struct {
+	// URL is the webhook handler to post to, the webhooks are only printed if empty.
+	// The original headers are posted, which are stale or duplicate to a handler with a Guard,
+	// so it should be a development handler without one.
+	URL string
+	// ContinueOnError continues with the next webhook if one fails.
+	ContinueOnError bool
+}
+
+// replay posts the stored webhooks selected by the filter to the target in order,
+// or prints them if the target URL is empty.
+// Each webhook is reported, and the failed ones are counted in the error.
+func replay(ctx context.Context, r io.Reader, filter *paypal.ReplayFilter, target *replayTarget,
+	out io.Writer,
+) (n int, err error) {
+	failed := 0
+	err = paypal.ReadWebhooks(r, func(sw *paypal.StoredWebhook) error {
+		wh, err := sw.Webhook()
+		if err != nil {
+			return err
+		}
+		if !filter.Match(wh) {
+			return nil
+		}
+		fmt.Fprintf(out, "%s %s %s", wh.CreateTime.Format(time.RFC3339), wh.EventType, wh.ID)
+		if target.URL != "" {
+			if err = forward(ctx, target.URL, sw.Header, []byte(sw.Body)); err != nil {
+				fmt.Fprintf(out, " failed: %v\n", err)
+				if !target.ContinueOnError {
+					return fmt.Errorf("replay %s: %w", wh.ID, err)
+				}
+				failed++
+				return nil
+			}
+		}
+		fmt.Fprintln(out)
+		n++
+		return nil
+	})
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%d webhooks failed", failed)
+	}
+	return
+}
+
+// forward posts the webhook with the original headers to the target.
+func forward(ctx context.Context, target string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal"
+)
+
+func Test_replay(t *testing.T) {
+	stream := `{"received_at":"2023-01-05T00:00:00Z","header":{"Paypal-Transmission-Id":["T1"]},"body":"{\"id\":\"WH-1\",\"event_type\":\"PAYMENT.CAPTURE.COMPLETED\",\"create_time\":\"2023-01-01T00:00:00Z\"}"}
+{"received_at":"2023-01-05T00:00:00Z","header":{"Paypal-Transmission-Id":["T2"]},"body":"{\"id\":\"WH-2\",\"event_type\":\"CHECKOUT.ORDER.APPROVED\",\"create_time\":\"2023-01-02T00:00:00Z\"}"}
+`
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, _ := io.ReadAll(r.Body)
+		got = append(got, r.Header.Get(paypal.HeaderTransmissionID)+" "+string(bs))
+	}))
+	defer srv.Close()
+
+	out := new(bytes.Buffer)
+	filter := &paypal.ReplayFilter{Patterns: []string{"PAYMENT.*"}}
+	n, err := replay(context.Background(), strings.NewReader(stream), filter, &replayTarget{URL: srv.URL}, out)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "2023-01-01T00:00:00Z PAYMENT.CAPTURE.COMPLETED WH-1\n", out.String())
+	assert.Equal(t, []string{
+		`T1 {"id":"WH-1","event_type":"PAYMENT.CAPTURE.COMPLETED","create_time":"2023-01-01T00:00:00Z"}`,
+	}, got)
+
+	out.Reset()
+	filter = &paypal.ReplayFilter{Since: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)}
+	n, err = replay(context.Background(), strings.NewReader(stream), filter, &replayTarget{}, out)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "2023-01-02T00:00:00Z CHECKOUT.ORDER.APPROVED WH-2\n", out.String())
+	assert.Len(t, got, 1)
+}
+
+func Test_replayDevHandler(t *testing.T) {
+	stream := `{"received_at":"2023-01-05T00:00:00Z","header":{"Paypal-Transmission-Id":["T1"]},"body":"{\"id\":\"WH-1\",\"event_type\":\"PAYMENT.CAPTURE.COMPLETED\",\"create_time\":\"2023-01-01T00:00:00Z\"}"}
+{"received_at":"2023-01-05T00:00:00Z","header":{"Paypal-Transmission-Id":["T2"]},"body":"{\"id\":\"WH-2\",\"event_type\":\"CHECKOUT.ORDER.APPROVED\",\"create_time\":\"2023-01-02T00:00:00Z\"}"}
+`
+	// A development handler without a guard, which handles the replayed webhooks again
+	var handled []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wh := new(paypal.Webhook)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(wh))
+		if wh.ID == "WH-1" && len(handled) == 0 {
+			handled = append(handled, "fail")
+			http.Error(w, "database is down", http.StatusInternalServerError)
+			return
+		}
+		handled = append(handled, wh.ID)
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	ctx := context.Background()
+
+	out := new(bytes.Buffer)
+	n, err := replay(ctx, strings.NewReader(stream), &paypal.ReplayFilter{}, &replayTarget{URL: srv.URL}, out)
+	require.Error(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, "2023-01-01T00:00:00Z PAYMENT.CAPTURE.COMPLETED WH-1 failed: status 500\n", out.String())
+
+	rt := &replayTarget{URL: srv.URL, ContinueOnError: true}
+	n, err = replay(ctx, strings.NewReader(stream), &paypal.ReplayFilter{}, rt, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"fail", "WH-1", "WH-2"}, handled)
+
+	srv.Close()
+	out.Reset()
+	n, err = replay(ctx, strings.NewReader(stream), &paypal.ReplayFilter{}, rt, out)
+	require.EqualError(t, err, "2 webhooks failed")
+	assert.Zero(t, n)
+	assert.Equal(t, 2, strings.Count(out.String(), " failed: post: "))
+}
@@ -315,13 +315,21 @@ func (p *Parser) Comment() (res Comment) {
 		t = append(t, reA.ReplaceAll(bs, link)...)
 		res.Links = append(res.Links, Link{
 			Title: string(matches[2]),
-			URL:   docBaseURL + string(matches[1]),
+			URL:   absURL(string(matches[1])),
 		})
 	}
 	res.Content = t
 	return
 }
 
+// absURL returns the absolute URL of the href in the PayPal documents.
+func absURL(href string) string {
+	if strings.HasPrefix(href, "https://") || strings.HasPrefix(href, "http://") {
+		return href
+	}
+	return docBaseURL + href
+}
+
 func (p *Parser) ReplaceCode() (res *Parser) {
 	res = p
 	codes := reCode.FindAll(p.bs, -1)
@@ -395,13 +403,19 @@ func isWhitespace(r byte) bool {
 type Generator struct {
 	Package string
 	sb      strings.Builder
+	infos   map[string]*EventInfo
 }
 
 func (g *Generator) Build(gs []WebhookGroup) string {
+	g.infos = map[string]*EventInfo{}
+	for _, info := range EventInfos(gs) {
+		g.infos[info.Event] = info
+	}
 	g.AppendHeader()
 	for _, wg := range gs {
 		g.AppendGroup(wg)
 	}
+	g.AppendRegistry(gs)
 	return g.sb.String()
 }
 
@@ -444,14 +458,15 @@ func (g *Generator) AppendWebhooks(webhooks map[string][]Webhook) {
 			g.Writeln()
 		}
 		g.sb.WriteString("const (")
-		for _, hook := range v {
+		for i := range v {
+			hook := v[i]
 			g.Writeln()
 			trigger := hook.Trigger
 			trigger.Links = nil
 			hook.RelatedMethod.Links = append(hook.Trigger.Links, hook.RelatedMethod.Links...)
 			g.AppendComment(trigger, 1)
 			g.AppendRelatedMethod(hook.RelatedMethod)
-			if hook.Repeated || hook.IsRef() {
+			if info := g.infos[hook.Event]; info == nil || info.decl != &v[i] {
 				g.WritefIndent(1, "// (redeclared) %s EventType = \"%s\"\n", hook.ID, hook.Event)
 			} else {
 				g.WritefIndent(1, "%s EventType = \"%s\"\n", hook.ID, hook.Event)
@@ -501,3 +516,105 @@ func (g *Generator) WritelnIndent(indent int, a ...any) {
 	prefix := strings.Repeat("	", indent)
 	g.sb.WriteString(prefix + fmt.Sprintln(a...))
 }
+
+// EventInfo is the metadata of an event type, merged from all its webhooks.
+type EventInfo struct {
+	Webhook
+	Group      string
+	Versions   []string
+	Deprecated bool
+
+	decl *Webhook // The webhook declaring the constant
+}
+
+// RelatedMethodURL returns the URL of the first link of the related method.
+func (ei *EventInfo) RelatedMethodURL() string {
+	if len(ei.RelatedMethod.Links) == 0 {
+		return ""
+	}
+	return ei.RelatedMethod.Links[0].URL
+}
+
+func isDeprecated(c Comment) bool {
+	return bytes.Contains(bytes.ToLower(c.Content), []byte("deprecat"))
+}
+
+// EventInfos returns the metadata of the event types in the order of declaration.
+//
+// An event type may be declared by several webhooks, e.g. BILLING.SUBSCRIPTION.CREATED
+// by both the deprecated billing agreements and the subscriptions.
+// The group, the description and the related method are of the first non-deprecated webhook,
+// preferring the first declaration, and the event type is deprecated only if all the webhooks are.
+// The references, e.g. "See ...", are not declarations.
+func EventInfos(gs []WebhookGroup) (res []*EventInfo) {
+	infos := map[string]*EventInfo{}
+	for _, wg := range gs {
+		sortedRangeMap(wg.Webhooks, func(version string, v []Webhook) {
+			for i := range v {
+				hook := v[i]
+				info, ok := infos[hook.Event]
+				if !ok {
+					info = &EventInfo{Webhook: Webhook{ID: hook.ID, Event: hook.Event}}
+					infos[hook.Event] = info
+					res = append(res, info)
+				}
+				if version != "" && !slices.Contains(info.Versions, version) {
+					info.Versions = append(info.Versions, version)
+					slices.Sort(info.Versions)
+				}
+				if hook.IsRef() {
+					continue
+				}
+				deprecated := isDeprecated(wg.Description) ||
+					isDeprecated(hook.Trigger) || isDeprecated(hook.RelatedMethod)
+				if info.decl != nil && (deprecated && !info.Deprecated ||
+					deprecated == info.Deprecated && (hook.Repeated || !info.decl.Repeated)) {
+					continue
+				}
+				info.Webhook = hook
+				info.Group = wg.Title
+				info.Deprecated = deprecated
+				info.decl = &v[i]
+			}
+		})
+	}
+	return
+}
+
+func (g *Generator) AppendRegistry(gs []WebhookGroup) {
+	infos := EventInfos(gs)
+	g.Writeln()
+	g.Writeln("// eventTypeInfos is the metadata of the event types.")
+	g.Writeln("var eventTypeInfos = map[EventType]*eventTypeInfo{")
+	for _, info := range infos {
+		g.WritefIndent(1, "%s: {\n", info.ID)
+		g.WritefIndent(2, "group:            %q,\n", info.Group)
+		if len(info.Versions) > 0 {
+			g.WritefIndent(2, "versions:         []string{%s},\n", quoteJoin(info.Versions))
+		}
+		g.WritefIndent(2, "description:      %q,\n", info.Trigger.Content)
+		if u := info.RelatedMethodURL(); u != "" {
+			g.WritefIndent(2, "relatedMethodURL: %q,\n", u)
+		}
+		if info.Deprecated {
+			g.WritelnIndent(2, "deprecated:       true,")
+		}
+		g.WritelnIndent(1, "},")
+	}
+	g.Writeln("}")
+	g.Writeln()
+	g.Writeln("// allEventTypes is all the event types in the order of declaration.")
+	g.Writeln("var allEventTypes = []EventType{")
+	for _, info := range infos {
+		g.WritefIndent(1, "%s,\n", info.ID)
+	}
+	g.Writeln("}")
+}
+
+func quoteJoin(ss []string) string {
+	var qs []string
+	for _, s := range ss {
+		qs = append(qs, fmt.Sprintf("%q", s))
+	}
+	return strings.Join(qs, ", ")
+}
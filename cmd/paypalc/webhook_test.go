@@ -116,3 +116,94 @@ func Test_wrap(t *testing.T) {
 		assert.Equal(t, expected, wrap(s, 40))
 	})
 }
+
+func Test_absURL(t *testing.T) {
+	assert.Equal(t, "https://developer.paypal.com/docs/api/orders/v2/",
+		absURL("/docs/api/orders/v2/"))
+	assert.Equal(t, "https://developer.paypal.com/docs/api/orders/v2/",
+		absURL("https://developer.paypal.com/docs/api/orders/v2/"))
+}
+
+func TestEventInfos(t *testing.T) {
+	created := Webhook{
+		ID:      "PaymentCaptureCompleted",
+		Event:   "PAYMENT.CAPTURE.COMPLETED",
+		Trigger: Comment{Content: []byte("A payment capture completes.")},
+		RelatedMethod: Comment{
+			Content: []byte("[Capture authorized payment]"),
+			Links:   []Link{{"Capture authorized payment", "https://example.com/v2"}},
+		},
+	}
+	repeated := created
+	repeated.Repeated = true
+	repeated.RelatedMethod.Links = []Link{{"Show captured payment details", "https://example.com/v1"}}
+	ref := Webhook{
+		ID:      "PaymentCaptureCompleted",
+		Event:   "PAYMENT.CAPTURE.COMPLETED",
+		Trigger: Comment{Content: []byte("See [PaymentCaptureCompleted].")},
+	}
+	gs := []WebhookGroup{
+		{Title: "Payments", Webhooks: map[string][]Webhook{"V1": {repeated}, "V2": {created}}},
+		{
+			Title:       "Marketplaces",
+			Description: Comment{Content: []byte("Deprecated: the group is deprecated.")},
+			Webhooks:    map[string][]Webhook{"": {ref}},
+		},
+	}
+	infos := EventInfos(gs)
+	assert.Len(t, infos, 1)
+	assert.Equal(t, "Payments", infos[0].Group)
+	assert.Equal(t, []string{"V1", "V2"}, infos[0].Versions)
+	assert.Equal(t, "https://example.com/v2", infos[0].RelatedMethodURL())
+	assert.False(t, infos[0].Deprecated)
+
+	// The deprecated billing agreement is redeclared by the subscriptions
+	agreement := Webhook{
+		ID:      "BillingSubscriptionCreated",
+		Event:   "BILLING.SUBSCRIPTION.CREATED",
+		Trigger: Comment{Content: []byte("A billing agreement is created.")},
+		RelatedMethod: Comment{
+			Content: []byte("[Create agreement]"),
+			Links:   []Link{{"Create agreement", "https://example.com/agreements"}},
+		},
+	}
+	reactivated := Webhook{
+		ID:      "BillingSubscriptionReActivated",
+		Event:   "BILLING.SUBSCRIPTION.RE-ACTIVATED",
+		Trigger: Comment{Content: []byte("A billing agreement is re-activated.")},
+	}
+	subscription := Webhook{
+		Repeated: true,
+		ID:       "BillingSubscriptionCreated",
+		Event:    "BILLING.SUBSCRIPTION.CREATED",
+		Trigger:  Comment{Content: []byte("A subscription is created.")},
+		RelatedMethod: Comment{
+			Content: []byte("[Create subscription]"),
+			Links:   []Link{{"Create subscription", "https://example.com/subscriptions"}},
+		},
+	}
+	gs = []WebhookGroup{
+		{
+			Title:       "Billing plans and agreements",
+			Description: Comment{Content: []byte("Deprecated: The billing agreements are deprecated.")},
+			Webhooks:    map[string][]Webhook{"": {agreement, reactivated}},
+		},
+		{Title: "Subscriptions", Webhooks: map[string][]Webhook{"": {subscription}}},
+	}
+	infos = EventInfos(gs)
+	assert.Len(t, infos, 2)
+	assert.Equal(t, "BILLING.SUBSCRIPTION.CREATED", infos[0].Event)
+	assert.Equal(t, "Subscriptions", infos[0].Group)
+	assert.Equal(t, "A subscription is created.", string(infos[0].Trigger.Content))
+	assert.Equal(t, "https://example.com/subscriptions", infos[0].RelatedMethodURL())
+	assert.False(t, infos[0].Deprecated)
+	assert.Equal(t, "Billing plans and agreements", infos[1].Group)
+	assert.True(t, infos[1].Deprecated)
+
+	// The constant is declared by the chosen webhook
+	code := (&Generator{Package: "paypal"}).Build(gs)
+	assert.Contains(t, code, `agreements
+	// (redeclared) BillingSubscriptionCreated EventType = "BILLING.SUBSCRIPTION.CREATED"`)
+	assert.Contains(t, code, `subscriptions
+	BillingSubscriptionCreated EventType = "BILLING.SUBSCRIPTION.CREATED"`)
+}
@@ -0,0 +1,543 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/internal/operation"
+	"github.com/adobaai/paypal/ptesting"
+)
+
+// contractBackend is a PayPal API which the contract tests run against.
+type contractBackend struct {
+	name   string
+	client *Client
+	rec    *contractRecorder
+	// fake is the fake server, nil for the sandbox.
+	fake *ptesting.Server
+	// planID is the billing plan to create subscriptions with, skipped if empty.
+	planID   string
+	approver *ptesting.Approver
+	// webhook is a webhook signed by the backend for the webhook ID, skipped if nil.
+	webhook   *StoredWebhook
+	webhookID string
+}
+
+// contractBackends returns the fake server,
+// and the sandbox if the UT_PAYPAL_ID and UT_PAYPAL_SECRET are set,
+// so that the fake is proved to behave like the sandbox.
+// The subscriptions are created on the sandbox with the plan of UT_PAYPAL_PLAN_ID,
+// and the signature of the first webhook in the JSONL store UT_PAYPAL_WEBHOOK_FILE,
+// e.g. recorded by "paypalc webhook listen -store", is verified for UT_PAYPAL_WEBHOOK_ID.
+func contractBackends(t *testing.T) []*contractBackend {
+	s := ptesting.NewServer()
+	t.Cleanup(s.Close)
+	s.ClientID, s.ClientSecret = "id", "secret"
+	s.WebhookID = "1JE4291016473214C"
+	s.Signer = ptesting.NewWebhookSigner(s.WebhookID)
+	body := ptesting.R(ptesting.NewWebhookEvent(PaymentCaptureCompleted, nil).JSON()).NoError(t).V()
+	header := ptesting.R(s.Signer.Sign(body)).NoError(t).V()
+	res := []*contractBackend{{
+		name:      "Fake",
+		client:    NewClient(s.URL, "id", "secret"),
+		fake:      s,
+		planID:    "P-5ML4271244454362WXNWU5NQ",
+		approver:  ptesting.NewApprover(s),
+		webhook:   NewStoredWebhook(header, body, time.Now()),
+		webhookID: s.WebhookID,
+	}}
+	if os.Getenv("UT_PAYPAL_ID") != "" && os.Getenv("UT_PAYPAL_SECRET") != "" {
+		res = append(res, &contractBackend{
+			name:      "Sandbox",
+			client:    NewTestClient(),
+			planID:    os.Getenv("UT_PAYPAL_PLAN_ID"),
+			approver:  ptesting.NewApprover(nil),
+			webhook:   readContractWebhook(t, os.Getenv("UT_PAYPAL_WEBHOOK_FILE")),
+			webhookID: os.Getenv("UT_PAYPAL_WEBHOOK_ID"),
+		})
+	}
+	for _, b := range res {
+		b.rec = &contractRecorder{next: http.DefaultTransport}
+		b.client.SetTransport(b.rec)
+	}
+	return res
+}
+
+// readContractWebhook returns the first webhook in the JSONL store, or nil if the name is empty.
+func readContractWebhook(t *testing.T, name string) (res *StoredWebhook) {
+	if name == "" {
+		return nil
+	}
+	bs := ptesting.R(os.ReadFile(name)).NoError(t).V()
+	require.NoError(t, ReadWebhooks(bytes.NewReader(bs), func(sw *StoredWebhook) error {
+		if res == nil {
+			res = sw
+		}
+		return nil
+	}))
+	require.NotNil(t, res, "no webhook in %s", name)
+	return
+}
+
+// newClient returns a new client of the backend with the credentials.
+func (b *contractBackend) newClient(id, secret string) *Client {
+	c := NewClient(b.client.base, id, secret)
+	c.SetTransport(b.rec)
+	return c
+}
+
+// sandboxOnlyFields are the fields of the sandbox responses which the fake does not return,
+// as they depend on the sandbox accounts or the card processor, or are only for display.
+// They are removed before comparing with the golden files, so that the golden files
+// hold the fields returned by both backends.
+var sandboxOnlyFields = []string{
+	// Orders
+	"payee", "payer", "shipping",
+	"expiry", "available_networks", "bin_details",
+	"seller_protection", "seller_receivable_breakdown", "disbursement_mode",
+	"processor_response", "network_transaction_reference",
+	// Subscriptions
+	"start_time", "quantity", "shipping_amount", "subscriber", "plan_overridden", "billing_info",
+}
+
+// golden asserts the last raw response of the operation is equivalent to the golden file
+// testdata/contract_<name>.json, with [ptesting.VolatileFields], sandboxOnlyFields
+// and the ignored fields removed.
+// The responses are compared as they are rather than decoded, which drops the unknown fields,
+// so that the fake is proved to respond in the exact shape of the sandbox.
+// The golden files are to be recorded from the sandbox with -ptesting.update,
+// which runs after the fake and thus overwrites the ones of the fake.
+func (b *contractBackend) golden(t *testing.T, op, name string, ignored ...string) {
+	t.Helper()
+	body := b.rec.last(op)
+	require.NotEmpty(t, body, "no response of %s", op)
+	fields := append(append([]string(nil), ptesting.VolatileFields...), sandboxOnlyFields...)
+	ptesting.R(body, nil).NoError(t).Golden("contract_"+name, append(fields, ignored...)...)
+}
+
+// contractRecorder records the last raw response body of each operation.
+type contractRecorder struct {
+	next http.RoundTripper
+
+	mu     sync.Mutex
+	bodies map[string]json.RawMessage
+}
+
+func (r *contractRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if op, ok := operation.FromRequest(req); ok {
+		r.mu.Lock()
+		if r.bodies == nil {
+			r.bodies = map[string]json.RawMessage{}
+		}
+		r.bodies[op.Name] = body
+		r.mu.Unlock()
+	}
+	return res, nil
+}
+
+func (r *contractRecorder) last(op string) json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies[op]
+}
+
+// contract runs the test against each backend.
+func contract(t *testing.T, f func(t *testing.T, b *contractBackend)) {
+	for _, b := range contractBackends(t) {
+		b := b
+		t.Run(b.name, func(t *testing.T) {
+			f(t, b)
+		})
+	}
+}
+
+// skipFake skips the test on the fake, which does not implement the API.
+func skipFake(t *testing.T, b *contractBackend, api string) {
+	if b.fake != nil {
+		t.Skipf("the fake does not implement the %s API, which is covered on the sandbox only", api)
+	}
+}
+
+// assertContractError asserts the error is a PayPal error in the documented shape.
+func assertContractError(t *testing.T, err error, status int, name, issue string) *Error {
+	e := assertContractErrorShape(t, err)
+	assert.Equal(t, status, e.StatusCode)
+	assert.Equal(t, name, e.Name)
+	require.NotEmpty(t, e.Details)
+	assert.Equal(t, issue, e.Details[0].Issue)
+	assert.NotZero(t, e.Details[0].Description)
+	assert.NotZero(t, findLink(e.Links, "information_link"))
+	return e
+}
+
+// assertContractErrorShape asserts the error is a client error of PayPal with a name,
+// for the APIs whose exact errors are not documented.
+func assertContractErrorShape(t *testing.T, err error) *Error {
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	assert.GreaterOrEqual(t, e.StatusCode, 400)
+	assert.Less(t, e.StatusCode, 500)
+	assert.NotZero(t, e.Name)
+	assert.NotZero(t, e.Message)
+	assert.NotZero(t, e.DebugID)
+	return e
+}
+
+func TestContractAuth(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		ptesting.R(b.client.Auth(ctx)).NoError(t).Do(func(t *testing.T, it *Token) {
+			assert.Equal(t, "Bearer", it.TokenType)
+			assert.NotZero(t, it.AccessToken)
+			assert.NotZero(t, it.Scope)
+			assert.NotZero(t, it.AppID)
+			assert.NotZero(t, it.Nonce)
+			assert.Greater(t, it.ExpiresIn, 0)
+		})
+		b.golden(t, "Auth", "auth", "scope", "access_token", "app_id", "nonce", "expires_in")
+
+		ptesting.R(b.newClient("1234", "5678").Auth(ctx)).
+			EqualError(t, "invalid_client: Client Authentication failed")
+		b.golden(t, "Auth", "auth_invalid_client")
+
+		ptesting.R(b.client.AuthIDToken(ctx, &AuthIDTokenReq{})).NoError(t).Do(func(t *testing.T, it *Token) {
+			assert.Equal(t, "Bearer", it.TokenType)
+			assert.NotZero(t, it.AccessToken)
+			assert.NotZero(t, it.IDToken)
+		})
+
+		ptesting.R(b.client.GenerateClientToken(ctx, &GenerateClientTokenReq{})).NoError(t).
+			Do(func(t *testing.T, it *Token) {
+				assert.NotZero(t, it.ClientToken)
+				assert.Greater(t, it.ExpiresIn, 0)
+				assert.True(t, it.ExpiresAt().After(time.Now()))
+			})
+	})
+}
+
+func TestContractIdentity(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		_, err := b.client.ExchangeCode(ctx, &ExchangeCodeReq{Code: "C21AAINVALID"})
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+		assert.NotZero(t, e.Err)
+		assert.NotZero(t, e.ErrDesc)
+
+		t.Run("UserInfo", func(t *testing.T) {
+			skipFake(t, b, "Log in with PayPal")
+			refresh := os.Getenv("UT_PAYPAL_USER_REFRESH_TOKEN")
+			if refresh == "" {
+				t.Skip("UT_PAYPAL_USER_REFRESH_TOKEN is not set, " +
+					"which is the refresh token of a sandbox user who gave consent in the browser")
+			}
+			token := ptesting.R(b.client.RefreshToken(ctx, &RefreshTokenReq{RefreshToken: refresh})).
+				NoError(t).V()
+			ptesting.R(b.client.GetUserInfo(ctx, &GetUserInfoReq{Token: token})).NoError(t).
+				Do(func(t *testing.T, it *UserInfo) {
+					assert.NotZero(t, it.UserID)
+				})
+		})
+	})
+}
+
+func TestContractOrder(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		c := b.client
+		order := ptesting.R(c.CreateOrder(ctx, &CreateOrderReq{Order: &Order{
+			Intent: OICapture,
+			PurchaseUnits: []*PurchaseUnit{
+				{Amount: &Amount{CurrencyCode: "USD", Value: "12.12"}},
+			},
+		}})).NoError(t).V()
+		assert.Len(t, order.ID, 17)
+		assert.Equal(t, OSCreated, order.Status)
+		assert.NotZero(t, findLink(order.Links, "self"))
+		assert.NotZero(t, findLink(order.Links, "approve"))
+		b.golden(t, "CreateOrder", "order_created")
+
+		ptesting.R(c.GetOrder(ctx, &GetOrderReq{ID: order.ID})).NoError(t).Do(func(t *testing.T, it *Order) {
+			assert.Equal(t, order.ID, it.ID)
+			assert.Equal(t, OSCreated, it.Status)
+			require.Len(t, it.PurchaseUnits, 1)
+			assert.Equal(t, "default", it.PurchaseUnits[0].ReferenceID)
+			assert.Equal(t, "12.12", it.PurchaseUnits[0].Amount.Value)
+		})
+		b.golden(t, "GetOrder", "order_get")
+
+		_, err := c.GetOrder(ctx, &GetOrderReq{ID: "5O190127TN364715T"})
+		assertContractError(t, err, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		b.golden(t, "GetOrder", "order_get_not_found")
+
+		_, err = c.CaptureOrder(ctx, &CaptureOrderReq{ID: order.ID})
+		assertContractError(t, err, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "ORDER_NOT_APPROVED")
+		b.golden(t, "CaptureOrder", "order_capture_not_approved")
+
+		_, err = c.CaptureOrder(ctx, &CaptureOrderReq{ID: "5O190127TN364715T"})
+		assertContractError(t, err, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		b.golden(t, "CaptureOrder", "order_capture_not_found")
+
+		_, err = c.CreateOrder(ctx, &CreateOrderReq{Order: &Order{Intent: OICapture}})
+		e := assertContractError(t, err, http.StatusBadRequest, "INVALID_REQUEST", "MISSING_REQUIRED_PARAMETER")
+		assert.Equal(t, "/purchase_units", e.Details[0].Field)
+		assert.Equal(t, "body", e.Details[0].Location)
+		b.golden(t, "CreateOrder", "order_create_invalid")
+
+		t.Run("Capture", func(t *testing.T) {
+			// The test card approves the order without a buyer, on the sandbox as well
+			order := ptesting.R(c.CreateOrder(ctx, &CreateOrderReq{
+				Order: &Order{
+					Intent: OICapture,
+					PurchaseUnits: []*PurchaseUnit{
+						{Amount: &Amount{CurrencyCode: "USD", Value: "12.12"}},
+					},
+					PaymentSource: &PaymentSource{Card: &Card{
+						Name:         "Contract Test",
+						Number:       ptesting.TestCardNumber,
+						Expiry:       ptesting.TestCardExpiry,
+						SecurityCode: ptesting.TestCardSecurityCode,
+					}},
+				},
+				RequestID: "contract-" + order.ID,
+			})).NoError(t).V()
+			assert.Equal(t, OSApproved, order.Status)
+
+			captured := ptesting.R(c.CaptureOrder(ctx, &CaptureOrderReq{ID: order.ID})).NoError(t).V()
+			assert.Equal(t, OSCompleted, captured.Status)
+			require.NotNil(t, captured.PaymentSource)
+			require.NotNil(t, captured.PaymentSource.Card)
+			assert.Equal(t, "1111", captured.PaymentSource.Card.LastDigits)
+			assert.Equal(t, "VISA", captured.PaymentSource.Card.Brand)
+			require.Len(t, captured.Captures(), 1)
+			assert.Equal(t, CaptureCompleted, captured.Captures()[0].Status)
+			assert.Equal(t, "12.12", captured.Captures()[0].Amount.Value)
+			b.golden(t, "CaptureOrder", "order_captured")
+
+			_, err := c.CaptureOrder(ctx, &CaptureOrderReq{ID: order.ID})
+			assertContractError(t, err, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY",
+				"ORDER_ALREADY_CAPTURED")
+			b.golden(t, "CaptureOrder", "order_capture_already_captured")
+
+			t.Run("Tracking", func(t *testing.T) {
+				skipFake(t, b, "Tracking")
+				tracked := ptesting.R(c.AddTracking(ctx, &AddTrackingReq{
+					OrderID:          order.ID,
+					CaptureID:        captured.Captures()[0].ID,
+					TrackingNumber:   "CONTRACT" + strings.ToUpper(order.ID),
+					Carrier:          CarrierOther,
+					CarrierNameOther: "Contract",
+				})).NoError(t).V()
+				require.NotEmpty(t, tracked.PurchaseUnits)
+				require.NotNil(t, tracked.PurchaseUnits[0].Shipping)
+				require.NotEmpty(t, tracked.PurchaseUnits[0].Shipping.Trackers)
+				tracker := tracked.PurchaseUnits[0].Shipping.Trackers[0]
+				assert.Equal(t, captured.Captures()[0].ID, tracker.CaptureID())
+				require.NoError(t, c.UpdateTracking(ctx, &UpdateTrackingReq{
+					OrderID:   order.ID,
+					TrackerID: tracker.ID,
+					Patches:   []*Patch{{Op: "replace", Path: "/status", Value: TSCancelled}},
+				}))
+
+				_, err := c.GetTracker(ctx, &GetTrackerReq{ID: "NOT-A-TRACKER"})
+				assertContractErrorShape(t, err)
+
+				// The trackers of the Tracking API
+				tr := &Tracker{
+					TransactionID:    captured.Captures()[0].ID,
+					TrackingNumber:   "BATCH" + strings.ToUpper(order.ID),
+					Status:           TSShipped,
+					Carrier:          CarrierOther,
+					CarrierNameOther: "Contract",
+				}
+				batch := ptesting.R(c.AddTrackers(ctx, &AddTrackersReq{Trackers: []*Tracker{tr}})).NoError(t).V()
+				assert.Empty(t, batch.Errors)
+				require.Len(t, batch.TrackerIdentifiers, 1)
+				assert.Equal(t, tr.TrackingNumber, batch.TrackerIdentifiers[0].TrackingNumber)
+
+				tr.Status = TSCancelled
+				require.NoError(t, c.UpdateTracker(ctx, &UpdateTrackerReq{Tracker: tr}))
+				ptesting.R(c.GetTracker(ctx, &GetTrackerReq{ID: tr.TrackerID()})).NoError(t).
+					Do(func(t *testing.T, it *Tracker) {
+						assert.Equal(t, TSCancelled, it.Status)
+					})
+			})
+		})
+	})
+}
+
+func TestContractSubscription(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		c := b.client
+		_, err := c.GetSubscription(ctx, &GetSubscriptionReq{ID: "I-SF6PBRMK4EPJ"})
+		assertContractError(t, err, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		b.golden(t, "GetSubscription", "subscription_get_not_found")
+
+		if b.planID == "" {
+			t.Skip("UT_PAYPAL_PLAN_ID is not set")
+		}
+		start := time.Now().Add(-time.Minute)
+		sub := ptesting.R(c.CreateSubscription(ctx, &CreateSubscriptionReq{
+			Subscription: &Subscription{PlanID: b.planID},
+		})).NoError(t).V()
+		assert.NotZero(t, sub.ID)
+		assert.Equal(t, SSApprovalPending, sub.Status)
+		assert.True(t, sub.CreateTime.After(start), sub.CreateTime)
+		assert.NotZero(t, findLink(sub.Links, "approve"))
+		b.golden(t, "CreateSubscription", "subscription_created", "plan_id", "status_update_time")
+
+		ptesting.R(c.GetSubscription(ctx, &GetSubscriptionReq{ID: sub.ID})).NoError(t).
+			Do(func(t *testing.T, it *Subscription) {
+				assert.Equal(t, sub.ID, it.ID)
+				assert.Equal(t, b.planID, it.PlanID)
+				assert.Equal(t, SSApprovalPending, it.Status)
+			})
+		b.golden(t, "GetSubscription", "subscription_get", "plan_id", "status_update_time")
+
+		err = c.CancelSubscription(ctx, &CancelSubscriptionReq{ID: sub.ID, Reason: "Contract test"})
+		assertContractError(t, err, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY",
+			"SUBSCRIPTION_STATUS_INVALID")
+		b.golden(t, "CancelSubscription", "subscription_cancel_invalid")
+
+		t.Run("Activate", func(t *testing.T) {
+			b.approver.Approve(t, findLink(sub.Links, "approve"), func(ctx context.Context) (bool, error) {
+				it, err := c.GetSubscription(ctx, &GetSubscriptionReq{ID: sub.ID})
+				return err == nil && it.Status == SSActive, err
+			})
+			ptesting.R(c.GetSubscription(ctx, &GetSubscriptionReq{ID: sub.ID})).NoError(t).
+				Do(func(t *testing.T, it *Subscription) {
+					assert.Equal(t, SSActive, it.Status)
+					assert.False(t, it.StatusUpdateTime.Before(it.CreateTime))
+				})
+
+			require.NoError(t, c.CancelSubscription(ctx, &CancelSubscriptionReq{ID: sub.ID, Reason: "Contract test"}))
+			ptesting.R(c.GetSubscription(ctx, &GetSubscriptionReq{ID: sub.ID})).NoError(t).
+				Do(func(t *testing.T, it *Subscription) {
+					assert.Equal(t, SSCancelled, it.Status)
+				})
+		})
+	})
+}
+
+func TestContractPartner(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		skipFake(t, b, "Partner Referrals")
+		_, err := b.client.GetPartnerReferral(ctx, &GetPartnerReferralReq{ID: "NOT-A-REFERRAL"})
+		assertContractErrorShape(t, err)
+
+		trackingID := "contract-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+		created := ptesting.R(b.client.CreatePartnerReferral(ctx, &CreatePartnerReferralReq{
+			PartnerReferral: &PartnerReferral{
+				TrackingID: trackingID,
+				Operations: []*ReferralOperationDetails{{
+					Operation: ROAPIIntegration,
+					APIIntegrationPreference: &APIIntegrationPreference{
+						RESTAPIIntegration: &RESTAPIIntegration{
+							IntegrationMethod: "PAYPAL",
+							IntegrationType:   "THIRD_PARTY",
+							ThirdPartyDetails: &ThirdPartyDetails{
+								Features: []IntegrationFeature{IFPayment, IFRefund},
+							},
+						},
+					},
+				}},
+				Products:      []ReferralProduct{RPExpressCheckout},
+				LegalConsents: []*LegalConsent{{Type: "SHARE_DATA_CONSENT", Granted: true}},
+			},
+		})).NoError(t).V()
+		assert.NotZero(t, created.ActionURL())
+		ptesting.R(b.client.GetPartnerReferral(ctx, &GetPartnerReferralReq{ID: created.ReferralID()})).
+			NoError(t).Do(func(t *testing.T, it *PartnerReferralData) {
+			assert.Equal(t, created.ReferralID(), it.PartnerReferralID)
+			require.NotNil(t, it.ReferralData)
+			assert.Equal(t, trackingID, it.ReferralData.TrackingID)
+		})
+
+		partnerID := os.Getenv("UT_PAYPAL_PARTNER_ID")
+		if partnerID == "" {
+			t.Skip("UT_PAYPAL_PARTNER_ID is not set, which is the merchant ID of the sandbox partner")
+		}
+		_, err = b.client.GetMerchantIntegration(ctx, &GetMerchantIntegrationReq{
+			PartnerID:  partnerID,
+			MerchantID: "NOTAMERCHANT",
+		})
+		assertContractErrorShape(t, err)
+
+		// The seller of the referral has not signed up
+		_, err = b.client.FindMerchantIntegration(ctx, &FindMerchantIntegrationReq{
+			PartnerID:  partnerID,
+			TrackingID: trackingID,
+		})
+		assertContractErrorShape(t, err)
+	})
+}
+
+func TestContractVerifyWebhookSign(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		// The signature of the example in the PayPal documents is not signed by the backend
+		body := json.RawMessage(testWebhookBody)
+		req, err := NewVerifyWSReq(newWebhookRequest(testWebhookBody).Header, body, "1JE4291016473214C")
+		require.NoError(t, err)
+		ptesting.R(b.client.VerifyWebhookSign(ctx, req)).NoError(t).Equal(false)
+		b.golden(t, "VerifyWebhookSign", "verify_webhook_sign_failure")
+
+		if b.webhook == nil || b.webhookID == "" {
+			t.Skip("UT_PAYPAL_WEBHOOK_FILE or UT_PAYPAL_WEBHOOK_ID is not set")
+		}
+		req, err = NewVerifyWSReq(b.webhook.Header, []byte(b.webhook.Body), b.webhookID)
+		require.NoError(t, err)
+		ptesting.R(b.client.VerifyWebhookSign(ctx, req)).NoError(t).Equal(true)
+		b.golden(t, "VerifyWebhookSign", "verify_webhook_sign_success")
+
+		req.WebhookEvent = json.RawMessage(strings.Replace(b.webhook.Body, `"id"`, `"id_"`, 1))
+		ptesting.R(b.client.VerifyWebhookSign(ctx, req)).NoError(t).Equal(false)
+	})
+}
+
+func TestContractSimulateWebhookEvent(t *testing.T) {
+	ctx := context.Background()
+	contract(t, func(t *testing.T, b *contractBackend) {
+		wh := ptesting.R(b.client.SimulateWebhookEvent(ctx, &SimulateWebhookEventReq{
+			URL:       "https://example.com/paypal/webhook",
+			EventType: PaymentCaptureCompleted,
+		})).NoError(t).V()
+		assert.NotZero(t, wh.ID)
+		assert.Equal(t, PaymentCaptureCompleted, wh.EventType)
+		assert.Equal(t, RTCapture, wh.ResourceType)
+		ptesting.R(ResourceAs[Capture](wh)).NoError(t).Do(func(t *testing.T, it *Capture) {
+			assert.NotZero(t, it.ID)
+			assert.Equal(t, CaptureCompleted, it.Status)
+		})
+		// The sample resources and summaries of the fake differ from the ones of PayPal
+		b.golden(t, "SimulateWebhookEvent", "simulate_webhook_event", "resource", "summary")
+
+		_, err := b.client.SimulateWebhookEvent(ctx, &SimulateWebhookEventReq{EventType: PaymentCaptureCompleted})
+		assertContractErrorShape(t, err)
+	})
+}
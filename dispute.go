@@ -0,0 +1,37 @@
+package paypal
+
+import "time"
+
+type DisputeStatus string
+
+const (
+	DSOpen             DisputeStatus = "OPEN"
+	DSWaitingForBuyer  DisputeStatus = "WAITING_FOR_BUYER_RESPONSE"
+	DSWaitingForSeller DisputeStatus = "WAITING_FOR_SELLER_RESPONSE"
+	DSUnderReview      DisputeStatus = "UNDER_REVIEW"
+	DSResolved         DisputeStatus = "RESOLVED"
+	DSOther            DisputeStatus = "OTHER"
+)
+
+// DisputedTransaction is a transaction for which a dispute was created.
+type DisputedTransaction struct {
+	SellerTransactionID string `json:"seller_transaction_id,omitempty"`
+	BuyerTransactionID  string `json:"buyer_transaction_id,omitempty"`
+}
+
+// Dispute is a customer dispute.
+//
+// See https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_get.
+type Dispute struct {
+	DisputeID             string                 `json:"dispute_id,omitempty"`
+	CreateTime            time.Time              `json:"create_time,omitempty"`
+	UpdateTime            time.Time              `json:"update_time,omitempty"`
+	DisputedTransactions  []*DisputedTransaction `json:"disputed_transactions,omitempty"`
+	Reason                string                 `json:"reason,omitempty"`
+	Status                DisputeStatus          `json:"status,omitempty"`
+	DisputeAmount         *Amount                `json:"dispute_amount,omitempty"`
+	DisputeLifeCycleStage string                 `json:"dispute_life_cycle_stage,omitempty"`
+	DisputeChannel        string                 `json:"dispute_channel,omitempty"`
+	SellerResponseDueDate time.Time              `json:"seller_response_due_date,omitempty"`
+	Links                 []*Link                `json:"links,omitempty"`
+}
@@ -0,0 +1,231 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Scopes of Log in with PayPal.
+//
+// See https://developer.paypal.com/docs/log-in-with-paypal/integrate/reference/#scope-attributes.
+const (
+	ScopeOpenID           = "openid"
+	ScopeProfile          = "profile"
+	ScopeEmail            = "email"
+	ScopeAddress          = "address"
+	ScopePayPalAttributes = "https://uri.paypal.com/services/paypalattributes"
+)
+
+type AuthorizeURLReq struct {
+	RedirectURI string   // Required, the return URL configured in the Developer Portal
+	Scopes      []string // Required, e.g. [ScopeOpenID], [ScopeEmail]
+	State       string   // Recommended, to prevent CSRF attacks
+	Nonce       string
+}
+
+// AuthorizeURL returns the URL of the consent page of Log in with PayPal.
+// After the user gives consent, PayPal redirects the user to the redirect URI
+// with the authorization code in the "code" query parameter,
+// which can be exchanged for tokens with [Client.ExchangeCode].
+//
+// See https://developer.paypal.com/docs/log-in-with-paypal/integrate/build-button/.
+func (c *Client) AuthorizeURL(req *AuthorizeURLReq) string {
+	q := url.Values{
+		"flowEntry":     {"static"},
+		"client_id":     {c.id},
+		"response_type": {"code"},
+		"scope":         {strings.Join(req.Scopes, " ")},
+		"redirect_uri":  {req.RedirectURI},
+	}
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	if req.Nonce != "" {
+		q.Set("nonce", req.Nonce)
+	}
+	return c.webBase() + "/signin/authorize?" + q.Encode()
+}
+
+// webBase returns the base URL of the PayPal website corresponding to the API base URL.
+func (c *Client) webBase() string {
+	u, err := url.Parse(c.base)
+	if err != nil {
+		return c.base
+	}
+	for _, prefix := range []string{"api-m.", "api."} {
+		if strings.HasPrefix(u.Host, prefix) {
+			u.Host = "www." + strings.TrimPrefix(u.Host, prefix)
+			break
+		}
+	}
+	return u.String()
+}
+
+type ExchangeCodeReq struct {
+	Code string // The authorization code from the redirect URI
+}
+
+// ExchangeCode exchanges the authorization code for an access token and a refresh token.
+//
+// See https://developer.paypal.com/docs/log-in-with-paypal/integrate/#link-getaccesstoken.
+func (c *Client) ExchangeCode(ctx context.Context, req *ExchangeCodeReq) (res *Token, err error) {
+	ctx = WithOperation(ctx, "ExchangeCode")
+	return c.requestToken(ctx, url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {req.Code},
+	})
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string
+}
+
+// RefreshToken requests a new access token with the refresh token
+// returned by [Client.ExchangeCode].
+//
+// See https://developer.paypal.com/docs/log-in-with-paypal/integrate/#link-exchangerefreshtokenforaccesstoken.
+func (c *Client) RefreshToken(ctx context.Context, req *RefreshTokenReq) (res *Token, err error) {
+	ctx = WithOperation(ctx, "RefreshToken")
+	res, err = c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {req.RefreshToken},
+	})
+	if err == nil && res.RefreshToken == "" {
+		// PayPal does not rotate the refresh token
+		res.RefreshToken = req.RefreshToken
+	}
+	return
+}
+
+type GenerateClientTokenReq struct {
+	// CustomerID is the ID of the customer in your system,
+	// the client token allows the JS SDK to render the vaulted payment methods of the customer.
+	CustomerID string `json:"customer_id,omitempty"`
+}
+
+// GenerateClientToken generates a client token for the JS SDK,
+// which is used as the data-client-token attribute to render
+// the Advanced Credit and Debit Card Payments (hosted card fields).
+// The token is returned in [Token.ClientToken] and expires at [Token.ExpiresAt].
+//
+// See https://developer.paypal.com/docs/multiparty/checkout/advanced/integrate/#link-generateclienttoken.
+func (c *Client) GenerateClientToken(ctx context.Context, req *GenerateClientTokenReq,
+) (res *Token, err error) {
+	ctx = WithOperation(ctx, "GenerateClientToken")
+	start := time.Now()
+	if res, err = JSON[Token](ctx, c, http.MethodPost, "/v1/identity/generate-token", req); err != nil {
+		return
+	}
+	res.setExpiresAt(start)
+	return
+}
+
+type AuthIDTokenReq struct {
+	// TargetCustomerID is the PayPal-generated customer ID of a vaulted customer,
+	// leave it empty for first-time payers.
+	TargetCustomerID string
+}
+
+// AuthIDToken requests an access token along with an ID token,
+// which is returned in [Token.IDToken] and used as the data-user-id-token attribute
+// of the JS SDK to save payment methods or render the vaulted payment methods.
+//
+// See https://developer.paypal.com/docs/checkout/save-payment-methods/during-purchase/js-sdk/paypal/#link-generateuseridtokenforreturningpayer.
+func (c *Client) AuthIDToken(ctx context.Context, req *AuthIDTokenReq) (res *Token, err error) {
+	ctx = WithOperation(ctx, "AuthIDToken")
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"response_type": {"id_token"},
+	}
+	if req.TargetCustomerID != "" {
+		form.Set("target_customer_id", req.TargetCustomerID)
+	}
+	return c.requestToken(ctx, form)
+}
+
+// UserAddress is the address of the user.
+type UserAddress struct {
+	StreetAddress string `json:"street_address,omitempty"`
+	Locality      string `json:"locality,omitempty"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code,omitempty"`
+	Country       string `json:"country,omitempty"`
+}
+
+// UserEmail is an email of the user.
+type UserEmail struct {
+	Value     string `json:"value,omitempty"`
+	Primary   bool   `json:"primary,omitempty"`
+	Confirmed bool   `json:"confirmed,omitempty"`
+}
+
+// UserInfo is the profile information of the user.
+// The fields are returned depending on the scopes that the user consented.
+//
+// See https://developer.paypal.com/docs/api/identity/v1/#userinfo_get.
+type UserInfo struct {
+	UserID          string       `json:"user_id,omitempty"`
+	Sub             string       `json:"sub,omitempty"`
+	Name            string       `json:"name,omitempty"`
+	GivenName       string       `json:"given_name,omitempty"`
+	FamilyName      string       `json:"family_name,omitempty"`
+	PayerID         string       `json:"payer_id,omitempty"`
+	Address         *UserAddress `json:"address,omitempty"`
+	VerifiedAccount bool         `json:"verified_account,omitempty"`
+	Emails          []*UserEmail `json:"emails,omitempty"`
+}
+
+// PrimaryEmail returns the primary email of the user, or "" if not found.
+func (ui *UserInfo) PrimaryEmail() string {
+	for _, e := range ui.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+type GetUserInfoReq struct {
+	// Token is the token returned by [Client.ExchangeCode] or [Client.RefreshToken].
+	Token *Token
+}
+
+// GetUserInfo shows the profile information of the user who gave consent.
+//
+// See https://developer.paypal.com/docs/api/identity/v1/#userinfo_get.
+func (c *Client) GetUserInfo(ctx context.Context, req *GetUserInfoReq) (res *UserInfo, err error) {
+	ctx = WithOperation(ctx, "GetUserInfo")
+	if req.Token == nil || req.Token.AccessToken == "" {
+		return nil, errors.New("token: missing access token")
+	}
+	url := c.base + "/v1/identity/oauth2/userinfo?schema=paypalv1.1"
+	hreq, err := NewJSONRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	hreq.Header.Set("Authorization", "Bearer "+req.Token.AccessToken)
+	return doJSON[UserInfo](ctx, c, hreq)
+}
+
+// ConsentRevocation is the resource of the [IdentityAuthorizationConsentRevoked] webhook.
+type ConsentRevocation struct {
+	UserID    string  `json:"user_id,omitempty"`
+	PayerID   string  `json:"payer_id,omitempty"`
+	ClientID  string  `json:"client_id,omitempty"`
+	Timestamp string  `json:"timestamp,omitempty"`
+	Links     []*Link `json:"links,omitempty"`
+}
+
+// ConsentRevocation returns the resource of the [IdentityAuthorizationConsentRevoked] webhook.
+// The tokens of the user should be dropped once the consent is revoked.
+func (wh *Webhook) ConsentRevocation() (res *ConsentRevocation, err error) {
+	if wh.EventType != IdentityAuthorizationConsentRevoked {
+		return nil, fmt.Errorf("unexpected event type: %s", wh.EventType)
+	}
+	return ResourceAs[ConsentRevocation](wh)
+}
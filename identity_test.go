@@ -0,0 +1,173 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestAuthorizeURL(t *testing.T) {
+	c := NewClient("https://api-m.sandbox.paypal.com", "AZ", "secret")
+	s := c.AuthorizeURL(&AuthorizeURLReq{
+		RedirectURI: "https://example.com/callback",
+		Scopes:      []string{ScopeOpenID, ScopeEmail},
+		State:       "xyz",
+	})
+	u := ptesting.R(url.Parse(s)).NoError(t).V()
+	assert.Equal(t, "www.sandbox.paypal.com", u.Host)
+	assert.Equal(t, "/signin/authorize", u.Path)
+	q := u.Query()
+	assert.Equal(t, "AZ", q.Get("client_id"))
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "openid email", q.Get("scope"))
+	assert.Equal(t, "https://example.com/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "xyz", q.Get("state"))
+}
+
+func TestIdentity(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		assert.Equal(t, "id", id)
+		assert.Equal(t, "secret", secret)
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.PostForm.Get("grant_type") == "authorization_code" && r.PostForm.Get("code") == "C21AA":
+			_, _ = w.Write([]byte(`{
+				"token_type": "Bearer",
+				"expires_in": 28800,
+				"refresh_token": "R23AA",
+				"access_token": "A23AA",
+				"id_token": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"
+			}`))
+		case r.PostForm.Get("grant_type") == "refresh_token" && r.PostForm.Get("refresh_token") == "R23AA":
+			_, _ = w.Write([]byte(`{"token_type": "Bearer", "expires_in": 28800, "access_token": "A24AA"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "Invalid authorization code"}`))
+		}
+	})
+	mux.HandleFunc("/v1/identity/oauth2/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "paypalv1.1", r.URL.Query().Get("schema"))
+		if r.Header.Get("Authorization") != "Bearer A24AA" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_token", "error_description": "Token signature verification failed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"user_id": "https://www.paypal.com/webapps/auth/identity/user/mWq6_1sU85v5EG9yHdPxJRrhGHrnMJ-1PQKtX6pcsmA",
+			"name": "identity test",
+			"payer_id": "WDJJHEBZ4X2LY",
+			"verified_account": true,
+			"emails": [{"value": "user1@example.com", "primary": true, "confirmed": true}]
+		}`))
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	c := NewClient(s.URL, "id", "secret")
+
+	var e *Error
+	ptesting.R(c.ExchangeCode(ctx, &ExchangeCodeReq{Code: "bad"})).ErrorAs(t, &e)
+	assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+	assert.Equal(t, "invalid_grant", e.Err)
+	assert.EqualError(t, e, "invalid_grant: Invalid authorization code")
+
+	token := ptesting.R(c.ExchangeCode(ctx, &ExchangeCodeReq{Code: "C21AA"})).NoError(t).V()
+	assert.True(t, token.Valid())
+	assert.Equal(t, "R23AA", token.RefreshToken)
+	assert.NotZero(t, token.IDToken)
+
+	ptesting.R(c.GetUserInfo(ctx, &GetUserInfoReq{Token: token})).ErrorAs(t, &e)
+	assert.Equal(t, "invalid_token", e.Err)
+
+	token = ptesting.R(c.RefreshToken(ctx, &RefreshTokenReq{RefreshToken: token.RefreshToken})).
+		NoError(t).V()
+	assert.Equal(t, "A24AA", token.AccessToken)
+	assert.Equal(t, "R23AA", token.RefreshToken)
+
+	ptesting.R(c.GetUserInfo(ctx, &GetUserInfoReq{Token: token})).NoError(t).
+		Do(func(t *testing.T, it *UserInfo) {
+			assert.Equal(t, "WDJJHEBZ4X2LY", it.PayerID)
+			assert.True(t, it.VerifiedAccount)
+			assert.Equal(t, "user1@example.com", it.PrimaryEmail())
+		})
+
+	ptesting.R(c.GetUserInfo(ctx, &GetUserInfoReq{})).ErrorContains(t, "missing access token")
+	ptesting.R(c.GetUserInfo(ctx, &GetUserInfoReq{Token: &Token{}})).ErrorContains(t, "missing access token")
+}
+
+func TestConsentRevocation(t *testing.T) {
+	var wh Webhook
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "WH-4M0448861G563140B-9EX36365822141321",
+		"event_type": "IDENTITY.AUTHORIZATION-CONSENT.REVOKED",
+		"resource": {
+			"user_id": "https://www.paypal.com/webapps/auth/identity/user/mWq6_1sU85v5EG9yHdPxJRrhGHrnMJ-1PQKtX6pcsmA",
+			"timestamp": "1584450785"
+		}
+	}`), &wh))
+	ptesting.R(wh.ConsentRevocation()).NoError(t).Do(func(t *testing.T, it *ConsentRevocation) {
+		assert.Contains(t, it.UserID, "mWq6_1sU85v5EG9yHdPxJRrhGHrnMJ-1PQKtX6pcsmA")
+		assert.Equal(t, "1584450785", it.Timestamp)
+	})
+
+	wh.EventType = PaymentCaptureCompleted
+	ptesting.R(wh.ConsentRevocation()).ErrorContains(t, "unexpected event type")
+}
+
+func TestGenerateClientToken(t *testing.T) {
+	ctx := context.Background()
+	h := ServeJSON(t, http.MethodPost, "/v1/identity/generate-token", 200, `{
+		"client_token": "eyJicmFpbnRyZWUiOnsiYXV0aG9yaXphdGlvbkZpbmdlcnByaW50IjoiYjA0MWE2M2JlMTM4M2NlZGUxZTI3OWFlNDlhMWIyNzZlY2FjOTYzOWU2NjlhMGIzODQyYTdkMTY3NzcwYmY0OHxtZXJjaGFudF9pZD1yd3dua3FnMnhnNTZobTJuJnB1YmxpY19rZXk9czlic3BuaGtxMmYzaDk0NiZjcmVhdGVkX2F0PTIwMTgtMTEtMTRUMTE6MTg6MDAuMTU3WiJ9",
+		"expires_in": 3600
+	}`)
+	c := NewMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateClientTokenReq
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "customer-1", req.CustomerID)
+		h.ServeHTTP(w, r)
+	}))
+	ptesting.R(c.GenerateClientToken(ctx, &GenerateClientTokenReq{CustomerID: "customer-1"})).
+		NoError(t).
+		Do(func(t *testing.T, it *Token) {
+			assert.NotZero(t, it.ClientToken)
+			assert.True(t, it.Valid())
+			assert.WithinDuration(t, time.Now().Add(time.Hour), it.ExpiresAt(), 5*time.Second)
+		})
+}
+
+func TestAuthIDToken(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "id_token", r.PostForm.Get("response_type"))
+		assert.Equal(t, "cus_1", r.PostForm.Get("target_customer_id"))
+		_, _ = w.Write([]byte(`{
+			"access_token": "A21AA",
+			"token_type": "Bearer",
+			"expires_in": 32400,
+			"id_token": "eyJraWQiOiI1N2Y0YjQ0ZjY3ZjA0NGM2ODIzN2U2YmI2MGU5ZWZjMyIsInR5cCI6IkpXVCIsImFsZyI6IkVTMjU2In0"
+		}`))
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	c := NewClient(s.URL, "id", "secret")
+	ptesting.R(c.AuthIDToken(ctx, &AuthIDTokenReq{TargetCustomerID: "cus_1"})).NoError(t).
+		Do(func(t *testing.T, it *Token) {
+			assert.NotZero(t, it.IDToken)
+			assert.True(t, it.ExpiresAt().After(time.Now()))
+		})
+}
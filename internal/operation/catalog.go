@@ -0,0 +1,123 @@
+package operation
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Operation is a PayPal API operation.
+type Operation struct {
+	Name   string
+	Method string
+	// Path is the path template, e.g. "/v2/checkout/orders/{order_id}",
+	// without the query.
+	Path string
+}
+
+// catalog are the operations of the client.
+// The operations of the same method and path, e.g. the token requests,
+// are matched in order by [Match].
+var catalog = []*Operation{
+	{"Auth", http.MethodPost, "/v1/oauth2/token"},
+	{"ExchangeCode", http.MethodPost, "/v1/oauth2/token"},
+	{"RefreshToken", http.MethodPost, "/v1/oauth2/token"},
+	{"AuthIDToken", http.MethodPost, "/v1/oauth2/token"},
+	{"GenerateClientToken", http.MethodPost, "/v1/identity/generate-token"},
+	{"GetUserInfo", http.MethodGet, "/v1/identity/oauth2/userinfo"},
+
+	{"CreateOrder", http.MethodPost, "/v2/checkout/orders"},
+	{"GetOrder", http.MethodGet, "/v2/checkout/orders/{order_id}"},
+	{"CaptureOrder", http.MethodPost, "/v2/checkout/orders/{order_id}/capture"},
+	{"AddTracking", http.MethodPost, "/v2/checkout/orders/{order_id}/track"},
+	{"UpdateTracking", http.MethodPatch, "/v2/checkout/orders/{order_id}/trackers/{tracker_id}"},
+	{"AddTrackers", http.MethodPost, "/v1/shipping/trackers-batch"},
+	{"GetTracker", http.MethodGet, "/v1/shipping/trackers/{tracker_id}"},
+	{"UpdateTracker", http.MethodPut, "/v1/shipping/trackers/{tracker_id}"},
+
+	{"CreateSubscription", http.MethodPost, "/v1/billing/subscriptions"},
+	{"GetSubscription", http.MethodGet, "/v1/billing/subscriptions/{subscription_id}"},
+	{"CancelSubscription", http.MethodPost, "/v1/billing/subscriptions/{subscription_id}/cancel"},
+
+	{"CreatePartnerReferral", http.MethodPost, "/v2/customer/partner-referrals"},
+	{"GetPartnerReferral", http.MethodGet, "/v2/customer/partner-referrals/{referral_id}"},
+	{"GetMerchantIntegration", http.MethodGet,
+		"/v1/customer/partners/{partner_id}/merchant-integrations/{merchant_id}"},
+	{"FindMerchantIntegration", http.MethodGet, "/v1/customer/partners/{partner_id}/merchant-integrations"},
+
+	{"VerifyWebhookSign", http.MethodPost, "/v1/notifications/verify-webhook-signature"},
+	{"SimulateWebhookEvent", http.MethodPost, "/v1/notifications/simulate-event"},
+}
+
+var byName = func() map[string]*Operation {
+	m := make(map[string]*Operation, len(catalog))
+	for _, op := range catalog {
+		m[op.Name] = op
+	}
+	return m
+}()
+
+// All returns a copy of the catalog.
+func All() []*Operation {
+	res := make([]*Operation, len(catalog))
+	for i, op := range catalog {
+		cp := *op
+		res[i] = &cp
+	}
+	return res
+}
+
+// Lookup returns the operation of the name in the catalog.
+func Lookup(name string) (op *Operation, ok bool) {
+	op, ok = byName[name]
+	return
+}
+
+// Match returns the first operation in the catalog of the method and the path,
+// and the values of the path parameters by name.
+func Match(method, path string) (op *Operation, params map[string]string, ok bool) {
+	for _, op := range catalog {
+		if op.Method != method {
+			continue
+		}
+		if params, ok = op.Match(path); ok {
+			return op, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// Match reports whether the path matches the path template of the operation,
+// and returns the values of the path parameters by name.
+func (op *Operation) Match(path string) (params map[string]string, ok bool) {
+	tsegs := strings.Split(strings.Trim(op.Path, "/"), "/")
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(tsegs) != len(segs) {
+		return nil, false
+	}
+	params = map[string]string{}
+	for i, t := range tsegs {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			if segs[i] == "" {
+				return nil, false
+			}
+			params[t[1:len(t)-1]] = segs[i]
+		} else if t != segs[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// FromRequest returns the operation of the request:
+// the one named in the request context, which is not necessarily in the catalog,
+// or else the one matched by the method and the path of the request, see [Path].
+func FromRequest(r *http.Request) (op *Operation, ok bool) {
+	if name, ok := From(r.Context()); ok {
+		if op, ok := Lookup(name); ok {
+			return op, true
+		}
+		return &Operation{Name: name, Method: r.Method}, true
+	}
+	op, _, ok = Match(r.Method, Path(r))
+	return
+}
@@ -0,0 +1,40 @@
+// Package operation carries the names of the PayPal API operations in contexts,
+// which is shared by the paypal package and the ptesting package.
+package operation
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type key struct{}
+
+// With returns a copy of the context with the operation name.
+func With(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, key{}, name)
+}
+
+// From returns the operation name in the context.
+func From(ctx context.Context) (name string, ok bool) {
+	name, ok = ctx.Value(key{}).(string)
+	return
+}
+
+type basePathKey struct{}
+
+// WithBasePath returns a copy of the context with the path of the base URL,
+// e.g. "/paypal" of "https://proxy.example.com/paypal",
+// which is stripped from the request paths before matching.
+func WithBasePath(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, basePathKey{}, strings.TrimSuffix(path, "/"))
+}
+
+// Path returns the path of the request without the base path in the request context.
+func Path(r *http.Request) string {
+	base, _ := r.Context().Value(basePathKey{}).(string)
+	if base != "" && strings.HasPrefix(r.URL.Path, base+"/") {
+		return r.URL.Path[len(base):]
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,176 @@
+// Package webhook holds the details of the PayPal webhooks,
+// which are shared by the paypal package, the ptesting package and paypalc.
+package webhook
+
+import (
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// Headers of the webhook requests sent by PayPal.
+const (
+	HeaderTransmissionID   = "PAYPAL-TRANSMISSION-ID"
+	HeaderTransmissionTime = "PAYPAL-TRANSMISSION-TIME"
+	HeaderTransmissionSig  = "PAYPAL-TRANSMISSION-SIG"
+	HeaderCertURL          = "PAYPAL-CERT-URL"
+	HeaderAuthAlgo         = "PAYPAL-AUTH-ALGO"
+)
+
+// SignedString returns the string signed by PayPal,
+// "<transmission_id>|<transmission_time>|<webhook_id>|<crc32>",
+// where crc32 is the decimal CRC32 checksum of the raw body.
+func SignedString(transmissionID, transmissionTime, webhookID string, body []byte) string {
+	return strings.Join([]string{
+		transmissionID,
+		transmissionTime,
+		webhookID,
+		strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 10),
+	}, "|")
+}
+
+// Resource types of webhooks.
+const (
+	RTCheckoutOrder = "checkout-order"
+	RTCapture       = "capture"
+	RTAuthorization = "authorization"
+	RTRefund        = "refund"
+	RTSale          = "sale"
+	RTSubscription  = "subscription"
+	RTDispute       = "dispute"
+	RTInvoices      = "invoices"
+	RTPayouts       = "payouts"
+	RTPayoutsItem   = "payouts_item"
+)
+
+// Resource is the resource of the events of an event type prefix.
+type Resource struct {
+	// Prefix is the prefix of the event types, e.g. "PAYMENT.CAPTURE.".
+	Prefix  string
+	Type    string
+	Version string
+	// Sample returns a minimal resource with the ID and the status,
+	// in the shape of the typed resource of the paypal package.
+	Sample func(id, status string) map[string]any
+	// Statuses are the statuses of the resources by the rests of the event types after the prefix,
+	// e.g. "ACTIVE" for "ACTIVATED" of "BILLING.SUBSCRIPTION.ACTIVATED";
+	// the rest is the status if missing.
+	Statuses map[string]string
+}
+
+func amount(value string) map[string]any {
+	return map[string]any{"currency_code": "USD", "value": value}
+}
+
+func withAmount(id, status string) map[string]any {
+	return map[string]any{"id": id, "status": status, "amount": amount("10.00")}
+}
+
+// resources are the resources by the event type prefixes.
+var resources = []*Resource{
+	{
+		Prefix: "CHECKOUT.ORDER.", Type: RTCheckoutOrder, Version: "2.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{
+				"id":             id,
+				"intent":         "CAPTURE",
+				"status":         status,
+				"purchase_units": []any{map[string]any{"amount": amount("10.00")}},
+			}
+		},
+	},
+	{
+		Prefix: "PAYMENT.CAPTURE.", Type: RTCapture, Version: "2.0", Sample: withAmount,
+		Statuses: map[string]string{"DENIED": "DECLINED", "REVERSED": "REFUNDED"},
+	},
+	{Prefix: "PAYMENT.AUTHORIZATION.", Type: RTAuthorization, Version: "2.0", Sample: withAmount},
+	{Prefix: "PAYMENT.REFUND.", Type: RTRefund, Version: "2.0", Sample: withAmount},
+	{
+		Prefix: "PAYMENT.SALE.", Type: RTSale, Version: "1.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{
+				"id":     id,
+				"state":  strings.ToLower(status),
+				"amount": map[string]any{"total": "10.00", "currency": "USD"},
+			}
+		},
+	},
+	{
+		Prefix: "BILLING.SUBSCRIPTION.", Type: RTSubscription, Version: "2.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{"id": id, "status": status, "plan_id": "P-" + id}
+		},
+		Statuses: map[string]string{
+			"CREATED":        "APPROVAL_PENDING",
+			"ACTIVATED":      "ACTIVE",
+			"RE-ACTIVATED":   "ACTIVE",
+			"UPDATED":        "ACTIVE",
+			"PAYMENT.FAILED": "ACTIVE",
+		},
+	},
+	{
+		Prefix: "CUSTOMER.DISPUTE.", Type: RTDispute, Version: "1.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{"dispute_id": id, "status": status, "dispute_amount": amount("10.00")}
+		},
+		Statuses: map[string]string{"CREATED": "OPEN", "UPDATED": "OPEN"},
+	},
+	{
+		Prefix: "INVOICING.INVOICE.", Type: RTInvoices, Version: "2.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{"id": id, "status": status, "amount": amount("10.00")}
+		},
+		Statuses: map[string]string{"CREATED": "DRAFT", "UPDATED": "SENT"},
+	},
+	{
+		Prefix: "PAYMENT.PAYOUTSBATCH.", Type: RTPayouts, Version: "1.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{
+				"batch_header": map[string]any{"payout_batch_id": id, "batch_status": status},
+			}
+		},
+	},
+	{
+		Prefix: "PAYMENT.PAYOUTS-ITEM.", Type: RTPayoutsItem, Version: "1.0",
+		Sample: func(id, status string) map[string]any {
+			return map[string]any{"payout_item_id": id, "transaction_status": status}
+		},
+	},
+}
+
+// LookupResource returns the resource of the event type, e.g. "PAYMENT.CAPTURE.COMPLETED".
+func LookupResource(eventType string) (res *Resource, ok bool) {
+	for _, r := range resources {
+		if strings.HasPrefix(eventType, r.Prefix) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// ResourceType returns the resource type of the event type,
+// which is the lowercase second segment if the event type is not known,
+// e.g. "onboarding" for "MERCHANT.ONBOARDING.COMPLETED".
+func ResourceType(eventType string) string {
+	if r, ok := LookupResource(eventType); ok {
+		return r.Type
+	}
+	if parts := strings.Split(eventType, "."); len(parts) > 2 {
+		return strings.ToLower(parts[1])
+	}
+	return ""
+}
+
+// SampleResource returns a minimal resource of the event type with the ID,
+// or a resource with only the ID if the event type is not known.
+func SampleResource(eventType, id string) map[string]any {
+	r, ok := LookupResource(eventType)
+	if !ok {
+		return map[string]any{"id": id}
+	}
+	status := strings.TrimPrefix(eventType, r.Prefix)
+	if s, ok := r.Statuses[status]; ok {
+		status = s
+	}
+	return r.Sample(id, status)
+}
@@ -0,0 +1,38 @@
+package paypal
+
+type InvoiceStatus string
+
+const (
+	ISDraft             InvoiceStatus = "DRAFT"
+	ISSent              InvoiceStatus = "SENT"
+	ISScheduled         InvoiceStatus = "SCHEDULED"
+	ISPaid              InvoiceStatus = "PAID"
+	ISMarkedAsPaid      InvoiceStatus = "MARKED_AS_PAID"
+	ISCancelled         InvoiceStatus = "CANCELLED"
+	ISRefunded          InvoiceStatus = "REFUNDED"
+	ISPartiallyPaid     InvoiceStatus = "PARTIALLY_PAID"
+	ISPartiallyRefunded InvoiceStatus = "PARTIALLY_REFUNDED"
+	ISMarkedAsRefunded  InvoiceStatus = "MARKED_AS_REFUNDED"
+	ISUnpaid            InvoiceStatus = "UNPAID"
+	ISPaymentPending    InvoiceStatus = "PAYMENT_PENDING"
+)
+
+// InvoiceDetail is the details of an invoice.
+type InvoiceDetail struct {
+	InvoiceNumber string `json:"invoice_number,omitempty"`
+	Reference     string `json:"reference,omitempty"`
+	CurrencyCode  string `json:"currency_code,omitempty"`
+	InvoiceDate   string `json:"invoice_date,omitempty"` // YYYY-MM-DD
+}
+
+// Invoice is an invoice.
+//
+// See https://developer.paypal.com/docs/api/invoicing/v2/#invoices_get.
+type Invoice struct {
+	ID        string         `json:"id,omitempty"`
+	Status    InvoiceStatus  `json:"status,omitempty"`
+	Detail    *InvoiceDetail `json:"detail,omitempty"`
+	Amount    *Amount        `json:"amount,omitempty"`
+	DueAmount *Amount        `json:"due_amount,omitempty"`
+	Links     []*Link        `json:"links,omitempty"`
+}
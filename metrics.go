@@ -0,0 +1,237 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/adobaai/paypal/internal/operation"
+)
+
+// Attributes of the client spans and metrics.
+const (
+	attrOperation      = attribute.Key("paypal.operation")
+	attrErrorName      = attribute.Key("paypal.error.name")
+	attrErrorIssue     = attribute.Key("paypal.error.issue")
+	attrDebugID        = attribute.Key("paypal.debug_id")
+	attrOrderID        = attribute.Key("paypal.order.id")
+	attrSubscriptionID = attribute.Key("paypal.subscription.id")
+	attrRetryAttempt   = attribute.Key("paypal.retry.attempt")
+	attrReason         = attribute.Key("paypal.reason")
+)
+
+// Reasons of the retries and the token refreshes.
+const (
+	reasonMissing  = "missing"
+	reasonExpired  = "expired"
+	reasonRejected = "rejected"
+)
+
+// errorNameTransport is the error name of the requests failed without responses.
+const errorNameTransport = "TRANSPORT_ERROR"
+
+// maxPeekBytes limits the response bodies read for the metrics and the span attributes.
+const maxPeekBytes = 1 << 20
+
+// durationBuckets are the bucket boundaries in seconds of paypal.client.duration,
+// from the fast token requests to the slow captures.
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 0.75, 1, 1.5, 2, 3, 5, 10, 30}
+
+// clientMetrics are the instruments of the client, from the global meter provider:
+//
+//   - paypal.client.requests: the requests by operation, route and status code.
+//   - paypal.client.duration: the latency in seconds until the response headers.
+//   - paypal.client.errors: the failed requests by operation, route, PayPal error name and issue.
+//   - paypal.client.retries: the retries by operation and reason.
+//   - paypal.client.token_refreshes: the access token requests by reason.
+type clientMetrics struct {
+	// enabled reports whether the meter provider is not a no-op one.
+	enabled bool
+
+	requests       metric.Int64Counter
+	duration       metric.Float64Histogram
+	errors         metric.Int64Counter
+	retries        metric.Int64Counter
+	tokenRefreshes metric.Int64Counter
+}
+
+func newClientMetrics(mp metric.MeterProvider) *clientMetrics {
+	meter := mp.Meter(tracerName)
+	_, isNoop := mp.(noop.MeterProvider)
+	res := &clientMetrics{enabled: !isNoop}
+	var err error
+	if res.requests, err = meter.Int64Counter("paypal.client.requests",
+		metric.WithDescription("The number of the requests to PayPal."),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		otel.Handle(err)
+		res.requests = noop.Int64Counter{}
+	}
+	if res.duration, err = meter.Float64Histogram("paypal.client.duration",
+		metric.WithDescription("The latency of the requests to PayPal."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	); err != nil {
+		otel.Handle(err)
+		res.duration = noop.Float64Histogram{}
+	}
+	if res.errors, err = meter.Int64Counter("paypal.client.errors",
+		metric.WithDescription("The number of the failed requests to PayPal."),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		otel.Handle(err)
+		res.errors = noop.Int64Counter{}
+	}
+	if res.retries, err = meter.Int64Counter("paypal.client.retries",
+		metric.WithDescription("The number of the retried requests to PayPal."),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		otel.Handle(err)
+		res.retries = noop.Int64Counter{}
+	}
+	if res.tokenRefreshes, err = meter.Int64Counter("paypal.client.token_refreshes",
+		metric.WithDescription("The number of the access token requests."),
+		metric.WithUnit("{token}"),
+	); err != nil {
+		otel.Handle(err)
+		res.tokenRefreshes = noop.Int64Counter{}
+	}
+	return res
+}
+
+type retryKey struct{}
+
+type retryInfo struct {
+	attempt int
+	reason  string
+}
+
+// withRetry returns a context of the retry attempt of a request.
+func withRetry(ctx context.Context, attempt int, reason string) context.Context {
+	return context.WithValue(ctx, retryKey{}, &retryInfo{attempt: attempt, reason: reason})
+}
+
+// resourceParams are the path parameters of the resources recorded in the span attributes.
+var resourceParams = map[string]attribute.Key{
+	"order_id":        attrOrderID,
+	"subscription_id": attrSubscriptionID,
+}
+
+// createdResources are the operations with the ID of the created resource in the response body.
+var createdResources = map[string]attribute.Key{
+	"CreateOrder":        attrOrderID,
+	"CreateSubscription": attrSubscriptionID,
+}
+
+// instrumentedTransport records the metrics and the span attributes of each request.
+// It is wrapped by otelhttp, so that the span of the request is in the request context.
+//
+// The bodies of the error responses and of the created resources are peeked,
+// i.e. read into memory up to maxPeekBytes, for the error names and the resource IDs.
+// The peeking is skipped if neither the span is recording nor the metrics are enabled,
+// e.g. with the no-op providers; note the global meter provider before
+// [otel.SetMeterProvider] is not a no-op one, as it delegates to the one set later.
+type instrumentedTransport struct {
+	next    http.RoundTripper
+	metrics *clientMetrics
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	span := trace.SpanFromContext(ctx)
+	op, _ := operation.FromRequest(req)
+	if op == nil {
+		op = &operation.Operation{Method: req.Method}
+	}
+	attrs := []attribute.KeyValue{attrOperation.String(op.Name)}
+	if op.Path != "" {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(op.Path))
+		span.SetAttributes(semconv.HTTPRouteKey.String(op.Path))
+		params, _ := op.Match(operation.Path(req))
+		for name, v := range params {
+			if key, ok := resourceParams[name]; ok {
+				span.SetAttributes(key.String(v))
+			}
+		}
+	}
+	if ri, ok := ctx.Value(retryKey{}).(*retryInfo); ok {
+		span.AddEvent("retry", trace.WithAttributes(
+			attrRetryAttempt.Int(ri.attempt), attrReason.String(ri.reason)))
+	}
+	idKey, created := createdResources[op.Name]
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Seconds()
+	if err != nil {
+		t.metrics.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+		t.metrics.duration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+		t.metrics.errors.Add(ctx, 1, metric.WithAttributes(
+			append(attrs, attrErrorName.String(errorNameTransport))...))
+		return res, err
+	}
+
+	attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+	t.metrics.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	t.metrics.duration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+	if id := res.Header.Get("Paypal-Debug-Id"); id != "" {
+		span.SetAttributes(attrDebugID.String(id))
+	}
+	if res.StatusCode < 400 && (!created || !span.IsRecording()) ||
+		res.StatusCode >= 400 && !span.IsRecording() && !t.metrics.enabled {
+		return res, nil
+	}
+
+	bs, err := peekBody(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 400 {
+		var e Error
+		_ = json.Unmarshal(bs, &e)
+		name, issue := e.Name, ""
+		if name == "" {
+			name = e.Err
+		}
+		if len(e.Details) > 0 {
+			issue = e.Details[0].Issue
+		}
+		if e.DebugID != "" {
+			span.SetAttributes(attrDebugID.String(e.DebugID))
+		}
+		t.metrics.errors.Add(ctx, 1, metric.WithAttributes(append(attrs,
+			attrErrorName.String(name), attrErrorIssue.String(issue))...))
+		return res, nil
+	}
+	var v struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(bs, &v) == nil && v.ID != "" {
+		span.SetAttributes(idKey.String(v.ID))
+	}
+	return res, nil
+}
+
+// peekBody reads the response body and restores it for the later reading.
+func peekBody(res *http.Response) (bs []byte, err error) {
+	bs, err = io.ReadAll(io.LimitReader(res.Body, maxPeekBytes))
+	if err != nil {
+		res.Body.Close()
+		return
+	}
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(bs), res.Body), res.Body}
+	return
+}
@@ -0,0 +1,277 @@
+package paypal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	mnoop "go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	tnoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+// testTelemetry records the spans and the metric points.
+type testTelemetry struct {
+	mnoop.MeterProvider
+	tnoop.TracerProvider
+
+	mu      sync.Mutex
+	spans   []*testSpan
+	points  []*testPoint
+	buckets map[string][]float64
+}
+
+type testPoint struct {
+	name  string
+	value float64
+	attrs attribute.Set
+}
+
+type testSpan struct {
+	tnoop.Span
+	name   string
+	attrs  map[attribute.Key]attribute.Value
+	events []string
+}
+
+func (s *testSpan) IsRecording() bool {
+	return true
+}
+
+func (s *testSpan) SetAttributes(kvs ...attribute.KeyValue) {
+	for _, kv := range kvs {
+		s.attrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *testSpan) AddEvent(name string, _ ...trace.EventOption) {
+	s.events = append(s.events, name)
+}
+
+type testMeter struct {
+	mnoop.Meter
+	tt *testTelemetry
+}
+
+type testTracer struct {
+	tnoop.Tracer
+	tt *testTelemetry
+}
+
+type testInstrument struct {
+	mnoop.Int64Counter
+	mnoop.Float64Histogram
+	name string
+	tt   *testTelemetry
+}
+
+// newTestTelemetry sets the global providers to a new testTelemetry until the test ends.
+func newTestTelemetry(t *testing.T) *testTelemetry {
+	tt := &testTelemetry{}
+	otel.SetMeterProvider(tt)
+	otel.SetTracerProvider(tt)
+	t.Cleanup(func() {
+		otel.SetMeterProvider(mnoop.NewMeterProvider())
+		otel.SetTracerProvider(tnoop.NewTracerProvider())
+	})
+	return tt
+}
+
+func (tt *testTelemetry) Meter(string, ...metric.MeterOption) metric.Meter {
+	return &testMeter{tt: tt}
+}
+
+func (tt *testTelemetry) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &testTracer{tt: tt}
+}
+
+func (m *testMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &testInstrument{name: name, tt: m.tt}, nil
+}
+
+func (m *testMeter) Float64Histogram(name string, opts ...metric.Float64HistogramOption,
+) (metric.Float64Histogram, error) {
+	m.tt.mu.Lock()
+	if m.tt.buckets == nil {
+		m.tt.buckets = map[string][]float64{}
+	}
+	m.tt.buckets[name] = metric.NewFloat64HistogramConfig(opts...).ExplicitBucketBoundaries()
+	m.tt.mu.Unlock()
+	return &testInstrument{name: name, tt: m.tt}, nil
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	s := &testSpan{name: name, attrs: map[attribute.Key]attribute.Value{}}
+	cfg := trace.NewSpanStartConfig(opts...)
+	s.SetAttributes(cfg.Attributes()...)
+	tr.tt.mu.Lock()
+	tr.tt.spans = append(tr.tt.spans, s)
+	tr.tt.mu.Unlock()
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+func (i *testInstrument) Add(_ context.Context, v int64, opts ...metric.AddOption) {
+	i.record(float64(v), metric.NewAddConfig(opts).Attributes())
+}
+
+func (i *testInstrument) Record(_ context.Context, v float64, opts ...metric.RecordOption) {
+	i.record(v, metric.NewRecordConfig(opts).Attributes())
+}
+
+func (i *testInstrument) record(v float64, attrs attribute.Set) {
+	i.tt.mu.Lock()
+	defer i.tt.mu.Unlock()
+	i.tt.points = append(i.tt.points, &testPoint{name: i.name, value: v, attrs: attrs})
+}
+
+// count returns the number and the sum of the points of the metric with the attributes.
+func (tt *testTelemetry) count(name string, kvs ...attribute.KeyValue) (n int, sum float64) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+outer:
+	for _, p := range tt.points {
+		if p.name != name {
+			continue
+		}
+		for _, kv := range kvs {
+			if v, ok := p.attrs.Value(kv.Key); !ok || v != kv.Value {
+				continue outer
+			}
+		}
+		n++
+		sum += p.value
+	}
+	return
+}
+
+func (tt *testTelemetry) spansOf(name string) (res []*testSpan) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	for _, s := range tt.spans {
+		if s.name == name {
+			res = append(res, s)
+		}
+	}
+	return
+}
+
+func TestClientTelemetry(t *testing.T) {
+	ctx := context.Background()
+	tt := newTestTelemetry(t)
+	s := ptesting.NewServer()
+	defer s.Close()
+	ft := ptesting.NewFaultTransport(nil)
+	c := NewClient(s.URL, "id", "secret")
+	c.SetTransport(ft)
+
+	order := ptesting.R(c.CreateOrder(ctx, &CreateOrderReq{Order: &Order{
+		Intent:        OICapture,
+		PurchaseUnits: []*PurchaseUnit{{Amount: &Amount{CurrencyCode: "USD", Value: "1.00"}}},
+	}})).NoError(t).V()
+	_, err := c.CaptureOrder(ctx, &CaptureOrderReq{ID: order.ID})
+	require.Error(t, err)
+	ft.On("GetSubscription", ptesting.TokenExpired())
+	_, err = c.GetSubscription(ctx, &GetSubscriptionReq{ID: "I-SF6PBRMK4EPJ"})
+	require.Error(t, err)
+
+	// Metrics
+	create := attrOperation.String("CreateOrder")
+	n, sum := tt.count("paypal.client.requests", create, semconv.HTTPStatusCodeKey.Int(201),
+		semconv.HTTPRouteKey.String("/v2/checkout/orders"))
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1.0, sum)
+	n, _ = tt.count("paypal.client.duration", create)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, durationBuckets, tt.buckets["paypal.client.duration"])
+	n, _ = tt.count("paypal.client.requests", attrOperation.String("GetSubscription"))
+	assert.Equal(t, 2, n)
+
+	n, _ = tt.count("paypal.client.errors", attrOperation.String("CaptureOrder"),
+		attrErrorName.String("UNPROCESSABLE_ENTITY"), attrErrorIssue.String("ORDER_NOT_APPROVED"))
+	assert.Equal(t, 1, n)
+	n, _ = tt.count("paypal.client.errors", attrOperation.String("GetSubscription"),
+		attrErrorName.String("invalid_token"))
+	assert.Equal(t, 1, n)
+	n, _ = tt.count("paypal.client.errors", attrOperation.String("GetSubscription"),
+		attrErrorName.String("RESOURCE_NOT_FOUND"), attrErrorIssue.String("INVALID_RESOURCE_ID"))
+	assert.Equal(t, 1, n)
+	n, _ = tt.count("paypal.client.errors", create)
+	assert.Zero(t, n)
+
+	n, _ = tt.count("paypal.client.retries", attrOperation.String("GetSubscription"),
+		attrReason.String(reasonRejected))
+	assert.Equal(t, 1, n)
+	n, _ = tt.count("paypal.client.token_refreshes", attrReason.String(reasonMissing))
+	assert.Equal(t, 1, n)
+	n, _ = tt.count("paypal.client.token_refreshes", attrReason.String(reasonRejected))
+	assert.Equal(t, 1, n)
+
+	// Spans
+	spans := tt.spansOf("PayPal CreateOrder")
+	require.Len(t, spans, 1)
+	assert.Equal(t, order.ID, spans[0].attrs[attrOrderID].AsString())
+	assert.NotZero(t, spans[0].attrs[attrDebugID].AsString())
+
+	spans = tt.spansOf("PayPal CaptureOrder")
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/v2/checkout/orders/{order_id}/capture", spans[0].attrs[semconv.HTTPRouteKey].AsString())
+	assert.Equal(t, order.ID, spans[0].attrs[attrOrderID].AsString())
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	assert.Len(t, spans[0].attrs[attrDebugID].AsString(), 13)
+
+	spans = tt.spansOf("PayPal GetSubscription")
+	require.Len(t, spans, 2)
+	assert.Empty(t, spans[0].events)
+	assert.Equal(t, []string{"retry"}, spans[1].events)
+	assert.Equal(t, "I-SF6PBRMK4EPJ", spans[1].attrs[attrSubscriptionID].AsString())
+	assert.Equal(t, e.DebugID, spans[1].attrs[attrDebugID].AsString())
+}
+
+// roundTripperFunc is a http.RoundTripper of a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// readRecorder is a response body recording whether it is read.
+type readRecorder struct {
+	io.Reader
+	read bool
+}
+
+func (b *readRecorder) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *readRecorder) Close() error { return nil }
+
+func TestInstrumentedTransportNoop(t *testing.T) {
+	for _, code := range []int{http.StatusCreated, http.StatusUnprocessableEntity} {
+		body := &readRecorder{Reader: strings.NewReader(`{"id":"5O190127TN364715T","name":"UNPROCESSABLE_ENTITY"}`)}
+		next := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: code, Header: http.Header{}, Body: body}, nil
+		})
+		it := &instrumentedTransport{next: next, metrics: newClientMetrics(mnoop.NewMeterProvider())}
+		req := httptest.NewRequest(http.MethodPost, "/v2/checkout/orders", nil)
+		res, err := it.RoundTrip(req)
+		require.NoError(t, err)
+		assert.Same(t, body, res.Body, code)
+		assert.False(t, body.read, code)
+	}
+}
@@ -0,0 +1,376 @@
+package paypal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrInvalidPrecision    = errors.New("invalid precision")
+	ErrCurrencyMismatch    = errors.New("currency mismatch")
+	ErrMoneyOverflow       = errors.New("money overflow")
+)
+
+// Currency is a currency supported by PayPal.
+type Currency struct {
+	Code string // The three-character ISO-4217 currency code
+	Name string
+
+	// Digits is the number of decimal digits PayPal accepts for the currency,
+	// which may be less than the ISO-4217 minor unit, e.g. HUF and TWD.
+	Digits int
+}
+
+// currencies is the currencies supported by PayPal.
+//
+// See https://developer.paypal.com/api/rest/reference/currency-codes/.
+var currencies = map[string]*Currency{
+	"AUD": {"AUD", "Australian dollar", 2},
+	"BRL": {"BRL", "Brazilian real", 2},
+	"CAD": {"CAD", "Canadian dollar", 2},
+	"CNY": {"CNY", "Chinese Renmenbi", 2},
+	"CZK": {"CZK", "Czech koruna", 2},
+	"DKK": {"DKK", "Danish krone", 2},
+	"EUR": {"EUR", "Euro", 2},
+	"HKD": {"HKD", "Hong Kong dollar", 2},
+	"HUF": {"HUF", "Hungarian forint", 0},
+	"ILS": {"ILS", "Israeli new shekel", 2},
+	"JPY": {"JPY", "Japanese yen", 0},
+	"MYR": {"MYR", "Malaysian ringgit", 2},
+	"MXN": {"MXN", "Mexican peso", 2},
+	"TWD": {"TWD", "New Taiwan dollar", 0},
+	"NZD": {"NZD", "New Zealand dollar", 2},
+	"NOK": {"NOK", "Norwegian krone", 2},
+	"PHP": {"PHP", "Philippine peso", 2},
+	"PLN": {"PLN", "Polish złoty", 2},
+	"GBP": {"GBP", "Pound sterling", 2},
+	"SGD": {"SGD", "Singapore dollar", 2},
+	"SEK": {"SEK", "Swedish krona", 2},
+	"CHF": {"CHF", "Swiss franc", 2},
+	"THB": {"THB", "Thai baht", 2},
+	"USD": {"USD", "United States dollar", 2},
+}
+
+// LookupCurrency returns the currency with the given code if it is supported by PayPal.
+func LookupCurrency(code string) (res *Currency, ok bool) {
+	res, ok = currencies[code]
+	return
+}
+
+// Currencies returns all currencies supported by PayPal, sorted by code.
+func Currencies() (res []*Currency) {
+	for _, c := range currencies {
+		res = append(res, c)
+	}
+	slices.SortFunc(res, func(a, b *Currency) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return
+}
+
+// Money is an amount of money in a currency supported by PayPal,
+// which is stored as an integer of the smallest unit to make the arithmetic exact.
+//
+// Money is marshaled to JSON in the same format as [Amount],
+// and can be unmarshaled from the format of both [Amount] and [SaleAmount].
+// The zero value is an invalid Money without currency.
+type Money struct {
+	currency *Currency
+	units    int64
+}
+
+// NewMoney returns a Money of the smallest units of the currency,
+// e.g. NewMoney("USD", 1234) is 12.34 USD.
+func NewMoney(currency string, units int64) (res Money, err error) {
+	c, ok := currencies[currency]
+	if !ok {
+		return res, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	return Money{currency: c, units: units}, nil
+}
+
+// ParseMoney parses the decimal value string in the currency,
+// e.g. ParseMoney("USD", "12.34").
+// Values with more decimal digits than PayPal accepts are rejected,
+// e.g. "1.5" is valid for USD but invalid for JPY.
+func ParseMoney(currency, value string) (res Money, err error) {
+	c, ok := currencies[currency]
+	if !ok {
+		return res, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+
+	s := value
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if (intPart == "" && fracPart == "") || (hasDot && fracPart == "") ||
+		!isDigits(intPart) || !isDigits(fracPart) {
+		return res, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if hasDot && c.Digits == 0 || len(fracPart) > c.Digits {
+		return res, fmt.Errorf("%w: %s accepts %d decimal digits: %q",
+			ErrInvalidPrecision, c.Code, c.Digits, value)
+	}
+
+	digits := intPart + fracPart + strings.Repeat("0", c.Digits-len(fracPart))
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return res, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if neg {
+		units = -units
+	}
+	return Money{currency: c, units: units}, nil
+}
+
+// MustParseMoney is like [ParseMoney] but panics if the value cannot be parsed.
+func MustParseMoney(currency, value string) Money {
+	m, err := ParseMoney(currency, value)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Currency returns the currency code, or "" for the zero value.
+func (m Money) Currency() string {
+	if m.currency == nil {
+		return ""
+	}
+	return m.currency.Code
+}
+
+// Units returns the amount in the smallest units of the currency.
+func (m Money) Units() int64 {
+	return m.units
+}
+
+// Value returns the decimal value string accepted by PayPal, e.g. "12.34".
+func (m Money) Value() string {
+	digits := 0
+	if m.currency != nil {
+		digits = m.currency.Digits
+	}
+	units := uint64(m.units)
+	sign := ""
+	if m.units < 0 {
+		sign = "-"
+		units = -units
+	}
+	s := strconv.FormatUint(units, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// String returns the value with the currency code, e.g. "12.34 USD".
+func (m Money) String() string {
+	return m.Value() + " " + m.Currency()
+}
+
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+func (m Money) check(o Money) error {
+	if m.currency == nil || m.currency != o.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), o.Currency())
+	}
+	return nil
+}
+
+// Add returns m+o, both must be in the same currency.
+// It returns an error wrapping [ErrMoneyOverflow] if the result is out of the int64 units.
+func (m Money) Add(o Money) (res Money, err error) {
+	if err = m.check(o); err != nil {
+		return
+	}
+	units := m.units + o.units
+	if (o.units > 0) != (units > m.units) && o.units != 0 {
+		return res, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, m, o)
+	}
+	return Money{currency: m.currency, units: units}, nil
+}
+
+// Sub returns m-o, both must be in the same currency.
+// It returns an error wrapping [ErrMoneyOverflow] if the result is out of the int64 units.
+func (m Money) Sub(o Money) (res Money, err error) {
+	if err = m.check(o); err != nil {
+		return
+	}
+	units := m.units - o.units
+	if (o.units > 0) != (units < m.units) && o.units != 0 {
+		return res, fmt.Errorf("%w: %s - %s", ErrMoneyOverflow, m, o)
+	}
+	return Money{currency: m.currency, units: units}, nil
+}
+
+// Mul returns m multiplied by the quantity.
+// It returns an error wrapping [ErrMoneyOverflow] if the result is out of the int64 units.
+func (m Money) Mul(quantity int64) (res Money, err error) {
+	units := m.units * quantity
+	if m.units != 0 && (units/m.units != quantity || m.units == -1 && quantity == math.MinInt64) {
+		return res, fmt.Errorf("%w: %s * %d", ErrMoneyOverflow, m, quantity)
+	}
+	return Money{currency: m.currency, units: units}, nil
+}
+
+// Cmp compares m and o, which must be in the same currency, and returns:
+//
+//	-1 if m <  o
+//	 0 if m == o
+//	+1 if m >  o
+func (m Money) Cmp(o Money) (res int, err error) {
+	if err = m.check(o); err != nil {
+		return
+	}
+	switch {
+	case m.units < o.units:
+		res = -1
+	case m.units > o.units:
+		res = 1
+	}
+	return
+}
+
+// Equal reports whether m and o are in the same currency and have the same value.
+func (m Money) Equal(o Money) bool {
+	return m.currency == o.currency && m.units == o.units
+}
+
+// Allocate splits m by the ratios without losing any smallest unit,
+// the remainder is distributed one unit each to the first parts.
+// For example, allocating 0.05 USD by 3:7 results in 0.02 USD and 0.03 USD.
+func (m Money) Allocate(ratios ...int) (res []Money, err error) {
+	var total uint64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("negative ratio: %d", r)
+		}
+		var carry uint64
+		if total, carry = bits.Add64(total, uint64(r), 0); carry != 0 {
+			return nil, fmt.Errorf("%w: sum of ratios", ErrMoneyOverflow)
+		}
+	}
+	if total == 0 {
+		return nil, errors.New("sum of ratios is zero")
+	}
+
+	// The parts are |m|*r/total rounded toward zero in 128 bits,
+	// which never overflow as r <= total.
+	abs := uint64(m.units)
+	if m.units < 0 {
+		abs = -abs
+	}
+	res = make([]Money, len(ratios))
+	remainder := m.units
+	for i, r := range ratios {
+		hi, lo := bits.Mul64(abs, uint64(r))
+		q, _ := bits.Div64(hi, lo, total)
+		units := int64(q)
+		if m.units < 0 {
+			units = -units
+		}
+		res[i] = Money{currency: m.currency, units: units}
+		remainder -= units
+	}
+	one := int64(1)
+	if remainder < 0 {
+		one = -1
+	}
+	for i := 0; remainder != 0; i++ {
+		if ratios[i] == 0 {
+			continue
+		}
+		res[i].units += one
+		remainder -= one
+	}
+	return
+}
+
+// Split splits m into n equal parts, see [Money.Allocate].
+func (m Money) Split(n int) (res []Money, err error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of parts: %d", n)
+	}
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// Amount returns the [Amount] of the money.
+func (m Money) Amount() *Amount {
+	return &Amount{CurrencyCode: m.Currency(), Value: m.Value()}
+}
+
+// SaleAmount returns the [SaleAmount] of the money.
+func (m Money) SaleAmount() *SaleAmount {
+	return &SaleAmount{Currency: m.Currency(), Total: m.Value()}
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Amount())
+}
+
+func (m *Money) UnmarshalJSON(bs []byte) (err error) {
+	var v struct {
+		Amount
+		SaleAmount
+	}
+	if err = json.Unmarshal(bs, &v); err != nil {
+		return
+	}
+	if v.CurrencyCode != "" {
+		*m, err = v.Amount.Money()
+	} else {
+		*m, err = v.SaleAmount.Money()
+	}
+	return
+}
+
+// Money parses the amount, see [ParseMoney].
+func (a *Amount) Money() (Money, error) {
+	return ParseMoney(a.CurrencyCode, a.Value)
+}
+
+// Validate checks whether the currency is supported by PayPal
+// and the value has the valid precision for the currency.
+func (a *Amount) Validate() error {
+	_, err := a.Money()
+	return err
+}
+
+// Money parses the amount, see [ParseMoney].
+func (a *SaleAmount) Money() (Money, error) {
+	return ParseMoney(a.Currency, a.Total)
+}
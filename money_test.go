@@ -0,0 +1,157 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestParseMoney(t *testing.T) {
+	ptesting.R(ParseMoney("USD", "12.34")).NoError(t).Do(func(t *testing.T, it Money) {
+		assert.Equal(t, int64(1234), it.Units())
+		assert.Equal(t, "12.34", it.Value())
+		assert.Equal(t, "12.34 USD", it.String())
+	})
+	ptesting.R(ParseMoney("USD", "1.5")).NoError(t).Do(func(t *testing.T, it Money) {
+		assert.Equal(t, "1.50", it.Value())
+	})
+	ptesting.R(ParseMoney("USD", "-0.05")).NoError(t).Do(func(t *testing.T, it Money) {
+		assert.Equal(t, int64(-5), it.Units())
+		assert.Equal(t, "-0.05", it.Value())
+	})
+	ptesting.R(ParseMoney("EUR", ".5")).NoError(t).Do(func(t *testing.T, it Money) {
+		assert.Equal(t, "0.50", it.Value())
+	})
+	ptesting.R(ParseMoney("JPY", "1000")).NoError(t).Do(func(t *testing.T, it Money) {
+		assert.Equal(t, "1000", it.Value())
+	})
+
+	ptesting.R(ParseMoney("USD", "1.234")).ErrorIs(t, ErrInvalidPrecision)
+	ptesting.R(ParseMoney("JPY", "100.00")).ErrorIs(t, ErrInvalidPrecision)
+	ptesting.R(ParseMoney("HUF", "1.5")).ErrorIs(t, ErrInvalidPrecision)
+	ptesting.R(ParseMoney("TWD", "30.1")).ErrorIs(t, ErrInvalidPrecision)
+	ptesting.R(ParseMoney("XXX", "1")).ErrorIs(t, ErrUnsupportedCurrency)
+	for _, v := range []string{"", "-", ".", "1.", "1,00", "1e3", "+1", "0x10"} {
+		ptesting.R(ParseMoney("USD", v)).ErrorIs(t, ErrInvalidAmount, v)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := MustParseMoney("USD", "10.10")
+	b := MustParseMoney("USD", "0.20")
+	ptesting.R(a.Add(b)).NoError(t).Equal(MustParseMoney("USD", "10.30"))
+	ptesting.R(b.Sub(a)).NoError(t).Equal(MustParseMoney("USD", "-9.90"))
+	ptesting.R(b.Mul(3)).NoError(t).Equal(MustParseMoney("USD", "0.60"))
+	ptesting.R(a.Cmp(b)).NoError(t).Equal(1)
+	ptesting.R(b.Cmp(a)).NoError(t).Equal(-1)
+	ptesting.R(a.Cmp(a)).NoError(t).Equal(0)
+
+	jpy := MustParseMoney("JPY", "100")
+	ptesting.R(a.Add(jpy)).ErrorIs(t, ErrCurrencyMismatch)
+	ptesting.R(a.Cmp(Money{})).ErrorIs(t, ErrCurrencyMismatch)
+	assert.False(t, a.Equal(jpy))
+
+	t.Run("Overflow", func(t *testing.T) {
+		max := ptesting.R(NewMoney("USD", math.MaxInt64)).NoError(t).V()
+		min := ptesting.R(NewMoney("USD", math.MinInt64)).NoError(t).V()
+		one, negOne := MustParseMoney("USD", "0.01"), MustParseMoney("USD", "-0.01")
+		ptesting.R(max.Add(one)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(min.Add(negOne)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(min.Sub(one)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(max.Sub(negOne)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(max.Sub(one)).NoError(t).Do(func(t *testing.T, it Money) {
+			assert.Equal(t, int64(math.MaxInt64-1), it.Units())
+		})
+		ptesting.R(max.Mul(2)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(min.Mul(-1)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(negOne.Mul(math.MinInt64)).ErrorIs(t, ErrMoneyOverflow)
+		ptesting.R(max.Mul(-1)).NoError(t).Do(func(t *testing.T, it Money) {
+			assert.Equal(t, int64(-math.MaxInt64), it.Units())
+		})
+		assert.Equal(t, "-92233720368547758.08", min.Value())
+	})
+
+	t.Run("Allocate", func(t *testing.T) {
+		ptesting.R(MustParseMoney("USD", "0.05").Allocate(3, 7)).NoError(t).
+			Equal([]Money{MustParseMoney("USD", "0.02"), MustParseMoney("USD", "0.03")})
+		ptesting.R(MustParseMoney("USD", "-0.05").Allocate(1, 0, 1)).NoError(t).
+			Equal([]Money{
+				MustParseMoney("USD", "-0.03"),
+				MustParseMoney("USD", "0"),
+				MustParseMoney("USD", "-0.02"),
+			})
+		ptesting.R(MustParseMoney("USD", "1").Allocate(0, 0)).ErrorContains(t, "zero")
+
+		// The parts of large amounts do not overflow
+		max := ptesting.R(NewMoney("USD", math.MaxInt64)).NoError(t).V()
+		ptesting.R(max.Allocate(math.MaxInt32, math.MaxInt32)).NoError(t).Do(func(t *testing.T, it []Money) {
+			assert.Equal(t, int64(math.MaxInt64/2+1), it[0].Units())
+			assert.Equal(t, int64(math.MaxInt64/2), it[1].Units())
+		})
+		min := ptesting.R(NewMoney("USD", math.MinInt64)).NoError(t).V()
+		ptesting.R(min.Allocate(1, 2)).NoError(t).Do(func(t *testing.T, it []Money) {
+			assert.Equal(t, int64(math.MinInt64), it[0].Units()+it[1].Units())
+		})
+		ptesting.R(max.Allocate(math.MaxInt, math.MaxInt, math.MaxInt)).ErrorIs(t, ErrMoneyOverflow)
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		ptesting.R(MustParseMoney("JPY", "100").Split(3)).NoError(t).
+			Equal([]Money{
+				MustParseMoney("JPY", "34"),
+				MustParseMoney("JPY", "33"),
+				MustParseMoney("JPY", "33"),
+			})
+		ptesting.R(jpy.Split(0)).ErrorContains(t, "invalid number")
+	})
+}
+
+func TestMoneyJSON(t *testing.T) {
+	m := MustParseMoney("USD", "8.8")
+	bs := ptesting.R(json.Marshal(m)).NoError(t).V()
+	assert.JSONEq(t, `{"currency_code":"USD","value":"8.80"}`, string(bs))
+
+	var got Money
+	require.NoError(t, json.Unmarshal(bs, &got))
+	assert.Equal(t, m, got)
+
+	var sale Sale
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":{"total":"0.40","currency":"USD"}}`), &sale))
+	ptesting.R(sale.Amount.Money()).NoError(t).Equal(MustParseMoney("USD", "0.40"))
+	require.NoError(t, json.Unmarshal([]byte(`{"total":"0.40","currency":"USD"}`), &got))
+	assert.Equal(t, MustParseMoney("USD", "0.40"), got)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"currency_code":"JPY","value":"1.5"}`), &got))
+}
+
+func TestCurrencies(t *testing.T) {
+	cs := Currencies()
+	assert.Equal(t, "AUD", cs[0].Code)
+	assert.Equal(t, "USD", cs[len(cs)-1].Code)
+	c, ok := LookupCurrency("HUF")
+	assert.True(t, ok)
+	assert.Equal(t, 0, c.Digits)
+}
+
+func TestCreateOrderValidate(t *testing.T) {
+	c := NewMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}))
+	order := &Order{
+		Intent: OICapture,
+		PurchaseUnits: []*PurchaseUnit{
+			{Amount: MustParseMoney("USD", "1").Amount()},
+			{Amount: &Amount{CurrencyCode: "JPY", Value: "100.50"}},
+		},
+	}
+	ptesting.R(c.CreateOrder(context.Background(), &CreateOrderReq{Order: order})).
+		ErrorIs(t, ErrInvalidPrecision).
+		ErrorContains(t, "purchase_units[1].amount")
+}
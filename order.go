@@ -2,6 +2,7 @@ package paypal
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -48,7 +49,9 @@ const (
 //
 // See https://developer.paypal.com/docs/api/orders/v2/#orders_create!path=purchase_units&t=request.
 type PurchaseUnit struct {
-	Amount *Amount `json:"amount"` // Requried
+	// ReferenceID identifies the purchase unit in the order, "default" if not set.
+	ReferenceID string  `json:"reference_id,omitempty"`
+	Amount      *Amount `json:"amount"` // Requried
 
 	// Description is the purchase description.
 	//
@@ -57,7 +60,50 @@ type PurchaseUnit struct {
 	// Depending on type of character; (e.g. accented character, Japanese characters)
 	// the number of characters that can be specified as input
 	// might not equal the permissible max length.
-	Description string `json:"description"`
+	Description string `json:"description,omitempty"`
+
+	Shipping *Shipping `json:"shipping,omitempty"`
+	Payments *Payments `json:"payments,omitempty"` // Read only
+}
+
+// Shipping is the shipping details.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#definition-shipping_with_tracking_details.
+type Shipping struct {
+	Trackers []*OrderTracker `json:"trackers,omitempty"` // Read only
+}
+
+// Payments is the comprehensive history of payments for the purchase unit.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#definition-payment_collection.
+type Payments struct {
+	Captures []*Capture `json:"captures,omitempty"`
+}
+
+type CaptureStatus string
+
+const (
+	CaptureCompleted         CaptureStatus = "COMPLETED"
+	CaptureDeclined          CaptureStatus = "DECLINED"
+	CapturePartiallyRefunded CaptureStatus = "PARTIALLY_REFUNDED"
+	CapturePending           CaptureStatus = "PENDING"
+	CaptureRefunded          CaptureStatus = "REFUNDED"
+	CaptureFailed            CaptureStatus = "FAILED"
+)
+
+// Capture is a captured payment.
+//
+// See https://developer.paypal.com/docs/api/payments/v2/#captures_get.
+type Capture struct {
+	ID           string        `json:"id,omitempty"`
+	Status       CaptureStatus `json:"status,omitempty"`
+	Amount       *Amount       `json:"amount,omitempty"`
+	InvoiceID    string        `json:"invoice_id,omitempty"`
+	CustomID     string        `json:"custom_id,omitempty"`
+	FinalCapture bool          `json:"final_capture,omitempty"`
+	CreateTime   time.Time     `json:"create_time,omitempty"`
+	UpdateTime   time.Time     `json:"update_time,omitempty"`
+	Links        []*Link       `json:"links,omitempty"`
 }
 
 // Amount is the total order amount with an optional breakdown that provides details,
@@ -78,6 +124,22 @@ type Amount struct {
 //
 // See https://developer.paypal.com/docs/api/orders/v2/#definition-payment_source.
 type PaymentSource struct {
+	Card *Card `json:"card,omitempty"`
+}
+
+// Card is a payment card.
+// The number, the expiry and the security code are only in the requests,
+// and PayPal responds with the last digits, the brand and the type instead.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#definition-card_request.
+type Card struct {
+	Name         string `json:"name,omitempty"`
+	Number       string `json:"number,omitempty"`
+	Expiry       string `json:"expiry,omitempty"` // In the format of YYYY-MM
+	SecurityCode string `json:"security_code,omitempty"`
+	LastDigits   string `json:"last_digits,omitempty"`
+	Brand        string `json:"brand,omitempty"`
+	Type         string `json:"type,omitempty"`
 }
 
 // Order is the PayPal order.
@@ -88,13 +150,77 @@ type Order struct {
 	Intent        OrderIntent     `json:"intent,omitempty"`         // Required
 	PurchaseUnits []*PurchaseUnit `json:"purchase_units,omitempty"` // Required
 	Status        OrderStatus     `json:"status,omitempty"`
+	PaymentSource *PaymentSource  `json:"payment_source,omitempty"`
 	CreateTime    time.Time       `json:"create_time,omitempty"`
 	UpdateTime    time.Time       `json:"update_time,omitempty"`
 	Links         []*Link         `json:"links,omitempty"`
 }
 
+// Authorization is an authorized payment.
+//
+// See https://developer.paypal.com/docs/api/payments/v2/#authorizations_get.
+type Authorization struct {
+	ID             string    `json:"id,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	Amount         *Amount   `json:"amount,omitempty"`
+	InvoiceID      string    `json:"invoice_id,omitempty"`
+	CustomID       string    `json:"custom_id,omitempty"`
+	ExpirationTime time.Time `json:"expiration_time,omitempty"`
+	CreateTime     time.Time `json:"create_time,omitempty"`
+	UpdateTime     time.Time `json:"update_time,omitempty"`
+	Links          []*Link   `json:"links,omitempty"`
+}
+
+// Refund is a refund of a captured payment.
+//
+// See https://developer.paypal.com/docs/api/payments/v2/#refunds_get.
+type Refund struct {
+	ID          string    `json:"id,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Amount      *Amount   `json:"amount,omitempty"`
+	InvoiceID   string    `json:"invoice_id,omitempty"`
+	CustomID    string    `json:"custom_id,omitempty"`
+	NoteToPayer string    `json:"note_to_payer,omitempty"`
+	CreateTime  time.Time `json:"create_time,omitempty"`
+	UpdateTime  time.Time `json:"update_time,omitempty"`
+	Links       []*Link   `json:"links,omitempty"`
+}
+
+// Captures returns all captures of the order's purchase units.
+func (o *Order) Captures() (res []*Capture) {
+	for _, pu := range o.PurchaseUnits {
+		if pu.Payments != nil {
+			res = append(res, pu.Payments.Captures...)
+		}
+	}
+	return
+}
+
 type CreateOrderReq struct {
 	*Order
+	// RequestID is the PayPal-Request-Id header, which makes the request idempotent.
+	// PayPal requires it if the payment source is provided.
+	RequestID string `json:"-"`
+	// Prefer is the Prefer header, e.g. "return=representation" to return the complete order,
+	// as PayPal returns the ID, the status and the links only by default.
+	Prefer string `json:"-"`
+}
+
+// validate validates the amounts before sending the request,
+// as PayPal rejects the invalid precisions of some currencies.
+func (r *CreateOrderReq) validate() error {
+	if r.Order == nil {
+		return nil
+	}
+	for i, pu := range r.PurchaseUnits {
+		if pu.Amount == nil {
+			continue
+		}
+		if err := pu.Amount.Validate(); err != nil {
+			return fmt.Errorf("purchase_units[%d].amount: %w", i, err)
+		}
+	}
+	return nil
 }
 
 // CreateOrder creates an order.
@@ -102,7 +228,36 @@ type CreateOrderReq struct {
 // See https://developer.paypal.com/docs/api/orders/v2/#orders_create.
 func (c *Client) CreateOrder(ctx context.Context, req *CreateOrderReq) (res *Order, err error) {
 	ctx = WithOperation(ctx, "CreateOrder")
-	return JSON[Order](ctx, c, http.MethodPost, "/v2/checkout/orders", req)
+	if err = req.validate(); err != nil {
+		return
+	}
+	hreq, err := NewJSONRequest(ctx, http.MethodPost, c.base+"/v2/checkout/orders", req)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	if req.RequestID != "" {
+		hreq.Header.Set("PayPal-Request-Id", req.RequestID)
+	}
+	if req.Prefer != "" {
+		hreq.Header.Set("Prefer", req.Prefer)
+	}
+	hres, err := c.doAuth(ctx, hreq)
+	if err != nil {
+		return
+	}
+	return parseResp[Order](hres)
+}
+
+type GetOrderReq struct {
+	ID string
+}
+
+// GetOrder shows details for an order.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#orders_get.
+func (c *Client) GetOrder(ctx context.Context, req *GetOrderReq) (res *Order, err error) {
+	ctx = WithOperation(ctx, "GetOrder")
+	return JSON[Order](ctx, c, http.MethodGet, "/v2/checkout/orders/"+req.ID, nil)
 }
 
 type CaptureOrderReq struct {
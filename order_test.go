@@ -10,7 +10,7 @@ import (
 )
 
 func TestOrder(t *testing.T) {
-	c := NewTestClient()
+	c, _ := newTestClient(t)
 	ctx := context.Background()
 	t.Run("Create", func(t *testing.T) {
 		order := &Order{
@@ -0,0 +1,282 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// ReferralOperation is the operation to enable for the customer.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-operation.
+type ReferralOperation string
+
+const (
+	ROAPIIntegration             ReferralOperation = "API_INTEGRATION"
+	ROBankAddition               ReferralOperation = "BANK_ADDITION"
+	ROBillingAgreement           ReferralOperation = "BILLING_AGREEMENT"
+	ROContextualMarketingConsent ReferralOperation = "CONTEXTUAL_MARKETING_CONSENT"
+)
+
+// ReferralProduct is the PayPal product to enable for the customer.
+type ReferralProduct string
+
+const (
+	RPExpressCheckout   ReferralProduct = "EXPRESS_CHECKOUT"
+	RPPPPlus            ReferralProduct = "PPPLUS"
+	RPWebsitePaymentPro ReferralProduct = "WEBSITE_PAYMENT_PRO"
+	RPPPCP              ReferralProduct = "PPCP"
+	RPAdvancedVaulting  ReferralProduct = "ADVANCED_VAULTING"
+	RPPaymentMethods    ReferralProduct = "PAYMENT_METHODS"
+)
+
+// IntegrationFeature is the feature granted to the partner by the customer.
+type IntegrationFeature string
+
+const (
+	IFPayment             IntegrationFeature = "PAYMENT"
+	IFRefund              IntegrationFeature = "REFUND"
+	IFFuturePayment       IntegrationFeature = "FUTURE_PAYMENT"
+	IFDirectPayment       IntegrationFeature = "DIRECT_PAYMENT"
+	IFPartnerFee          IntegrationFeature = "PARTNER_FEE"
+	IFDelayFunds          IntegrationFeature = "DELAY_FUNDS_DISBURSEMENT"
+	IFReadSellerDispute   IntegrationFeature = "READ_SELLER_DISPUTE"
+	IFUpdateSellerDispute IntegrationFeature = "UPDATE_SELLER_DISPUTE"
+	IFAccessMerchantInfo  IntegrationFeature = "ACCESS_MERCHANT_INFORMATION"
+	IFVaulting            IntegrationFeature = "VAULT"
+	IFBillingAgreement    IntegrationFeature = "BILLING_AGREEMENT"
+)
+
+// ThirdPartyDetails is the details of the third-party integration.
+type ThirdPartyDetails struct {
+	Features []IntegrationFeature `json:"features,omitempty"`
+}
+
+// RESTAPIIntegration is the integration details for the PayPal REST endpoints.
+type RESTAPIIntegration struct {
+	// IntegrationMethod is either PAYPAL or BRAINTREE.
+	IntegrationMethod string `json:"integration_method,omitempty"`
+	// IntegrationType is either FIRST_PARTY or THIRD_PARTY.
+	IntegrationType   string             `json:"integration_type,omitempty"`
+	ThirdPartyDetails *ThirdPartyDetails `json:"third_party_details,omitempty"`
+}
+
+// APIIntegrationPreference is the preference of the customer for the API integration.
+type APIIntegrationPreference struct {
+	RESTAPIIntegration *RESTAPIIntegration `json:"rest_api_integration,omitempty"`
+}
+
+// ReferralOperationDetails is an operation to enable for the customer.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-operation.
+type ReferralOperationDetails struct {
+	Operation                ReferralOperation         `json:"operation"` // Required
+	APIIntegrationPreference *APIIntegrationPreference `json:"api_integration_preference,omitempty"`
+}
+
+// LegalConsent is the customer's consent to share data.
+type LegalConsent struct {
+	// Type is the type of consent, e.g. SHARE_DATA_CONSENT.
+	Type    string `json:"type"`
+	Granted bool   `json:"granted"`
+}
+
+// PartnerConfigOverride overrides the partner configuration for this referral.
+type PartnerConfigOverride struct {
+	PartnerLogoURL       string `json:"partner_logo_url,omitempty"`
+	ReturnURL            string `json:"return_url,omitempty"`
+	ReturnURLDescription string `json:"return_url_description,omitempty"`
+	ActionRenewalURL     string `json:"action_renewal_url,omitempty"`
+	ShowAddCreditCard    *bool  `json:"show_add_credit_card,omitempty"`
+}
+
+// PartnerReferral is the customer data shared by the partner with PayPal.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-referral_data.
+type PartnerReferral struct {
+	Email                 string                      `json:"email,omitempty"`
+	PreferredLanguageCode string                      `json:"preferred_language_code,omitempty"`
+	TrackingID            string                      `json:"tracking_id,omitempty"`
+	PartnerConfigOverride *PartnerConfigOverride      `json:"partner_config_override,omitempty"`
+	Operations            []*ReferralOperationDetails `json:"operations,omitempty"` // Required
+	Products              []ReferralProduct           `json:"products,omitempty"`
+	LegalConsents         []*LegalConsent             `json:"legal_consents,omitempty"` // Required
+}
+
+type CreatePartnerReferralReq struct {
+	*PartnerReferral
+}
+
+// CreatedPartnerReferral is the response of [Client.CreatePartnerReferral].
+type CreatedPartnerReferral struct {
+	Links []*Link `json:"links,omitempty"`
+}
+
+// ActionURL returns the URL the customer visits to sign up for PayPal,
+// i.e. the onboarding link.
+func (r *CreatedPartnerReferral) ActionURL() string {
+	return findLink(r.Links, "action_url")
+}
+
+// ReferralID returns the ID of the partner referral,
+// which can be used in [Client.GetPartnerReferral].
+func (r *CreatedPartnerReferral) ReferralID() string {
+	href := findLink(r.Links, "self")
+	if href == "" {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return path.Base(u.Path)
+}
+
+// CreatePartnerReferral creates a partner referral that is shared by the API caller.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create.
+func (c *Client) CreatePartnerReferral(ctx context.Context, req *CreatePartnerReferralReq,
+) (res *CreatedPartnerReferral, err error) {
+	ctx = WithOperation(ctx, "CreatePartnerReferral")
+	return JSON[CreatedPartnerReferral](ctx, c,
+		http.MethodPost, "/v2/customer/partner-referrals", req)
+}
+
+type GetPartnerReferralReq struct {
+	ID string
+}
+
+// PartnerReferralData is the partner referral data.
+type PartnerReferralData struct {
+	PartnerReferralID string           `json:"partner_referral_id,omitempty"`
+	SubmitterPayerID  string           `json:"submitter_payer_id,omitempty"`
+	ReferralData      *PartnerReferral `json:"referral_data,omitempty"`
+	Links             []*Link          `json:"links,omitempty"`
+}
+
+// GetPartnerReferral shows details by ID for referral data that was shared by the partner.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_read.
+func (c *Client) GetPartnerReferral(ctx context.Context, req *GetPartnerReferralReq,
+) (res *PartnerReferralData, err error) {
+	ctx = WithOperation(ctx, "GetPartnerReferral")
+	return JSON[PartnerReferralData](ctx, c,
+		http.MethodGet, "/v2/customer/partner-referrals/"+req.ID, nil)
+}
+
+// CapabilityStatus is the status of a merchant capability.
+type CapabilityStatus string
+
+const (
+	CSActive    CapabilityStatus = "ACTIVE"
+	CSSuspended CapabilityStatus = "SUSPENDED"
+	CSRevoked   CapabilityStatus = "REVOKED"
+)
+
+// Capability is a capability of the merchant, e.g. CUSTOM_CARD_PROCESSING.
+type Capability struct {
+	Name   string           `json:"name,omitempty"`
+	Status CapabilityStatus `json:"status,omitempty"`
+}
+
+// VettingStatus is the vetting status of a product.
+type VettingStatus string
+
+const (
+	VSSubscribed   VettingStatus = "SUBSCRIBED"
+	VSNeedMoreData VettingStatus = "NEED_MORE_DATA"
+	VSInReview     VettingStatus = "IN_REVIEW"
+	VSDenied       VettingStatus = "DENIED"
+	VSApproved     VettingStatus = "APPROVED"
+	VSPending      VettingStatus = "PENDING"
+)
+
+// MerchantProduct is a product enabled for the merchant.
+type MerchantProduct struct {
+	Name          string        `json:"name,omitempty"`
+	VettingStatus VettingStatus `json:"vetting_status,omitempty"`
+	Capabilities  []string      `json:"capabilities,omitempty"`
+}
+
+// OAuthThirdParty is the third party which the merchant granted permissions to.
+type OAuthThirdParty struct {
+	PartnerClientID  string   `json:"partner_client_id,omitempty"`
+	MerchantClientID string   `json:"merchant_client_id,omitempty"`
+	Scopes           []string `json:"scopes,omitempty"`
+}
+
+// OAuthIntegration is the OAuth integration of the merchant with the partner.
+type OAuthIntegration struct {
+	IntegrationType   string             `json:"integration_type,omitempty"`
+	IntegrationMethod string             `json:"integration_method,omitempty"`
+	OAuthThirdParty   []*OAuthThirdParty `json:"oauth_third_party,omitempty"`
+}
+
+// MerchantIntegration is the integration status of a merchant onboarded by the partner.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v1/#merchant-integration_status.
+type MerchantIntegration struct {
+	MerchantID            string              `json:"merchant_id,omitempty"`
+	TrackingID            string              `json:"tracking_id,omitempty"`
+	LegalName             string              `json:"legal_name,omitempty"`
+	PrimaryEmail          string              `json:"primary_email,omitempty"`
+	PaymentsReceivable    bool                `json:"payments_receivable"`
+	PrimaryEmailConfirmed bool                `json:"primary_email_confirmed"`
+	Products              []*MerchantProduct  `json:"products,omitempty"`
+	Capabilities          []*Capability       `json:"capabilities,omitempty"`
+	OAuthIntegrations     []*OAuthIntegration `json:"oauth_integrations,omitempty"`
+	Links                 []*Link             `json:"links,omitempty"`
+}
+
+// CanReceivePayments reports whether the merchant is ready to receive payments,
+// that is, the merchant can receive payments, has confirmed the primary email,
+// and has granted permissions to the partner.
+//
+// See https://developer.paypal.com/docs/multiparty/seller-onboarding/onboarding-checklist/.
+func (mi *MerchantIntegration) CanReceivePayments() bool {
+	return mi.PaymentsReceivable && mi.PrimaryEmailConfirmed && len(mi.OAuthIntegrations) > 0
+}
+
+// Capability returns the capability with the given name, or nil if not found.
+func (mi *MerchantIntegration) Capability(name string) *Capability {
+	for _, c := range mi.Capabilities {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+type GetMerchantIntegrationReq struct {
+	PartnerID  string // The partner merchant ID
+	MerchantID string // The seller merchant ID
+}
+
+// GetMerchantIntegration shows the status of a seller onboarded by the partner.
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v1/#merchant-integration_status.
+func (c *Client) GetMerchantIntegration(ctx context.Context, req *GetMerchantIntegrationReq,
+) (res *MerchantIntegration, err error) {
+	ctx = WithOperation(ctx, "GetMerchantIntegration")
+	path := "/v1/customer/partners/" + req.PartnerID + "/merchant-integrations/" + req.MerchantID
+	return JSON[MerchantIntegration](ctx, c, http.MethodGet, path, nil)
+}
+
+type FindMerchantIntegrationReq struct {
+	PartnerID  string // The partner merchant ID
+	TrackingID string // The tracking ID of the partner referral
+}
+
+// FindMerchantIntegration finds the merchant ID of a seller by the tracking ID
+// used in [Client.CreatePartnerReferral].
+// The MerchantID of the result can be used in [Client.GetMerchantIntegration].
+//
+// See https://developer.paypal.com/docs/api/partner-referrals/v1/#merchant-integration_find.
+func (c *Client) FindMerchantIntegration(ctx context.Context, req *FindMerchantIntegrationReq,
+) (res *MerchantIntegration, err error) {
+	ctx = WithOperation(ctx, "FindMerchantIntegration")
+	path := "/v1/customer/partners/" + req.PartnerID + "/merchant-integrations?" +
+		url.Values{"tracking_id": {req.TrackingID}}.Encode()
+	return JSON[MerchantIntegration](ctx, c, http.MethodGet, path, nil)
+}
@@ -0,0 +1,132 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestPartnerReferral(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Create", func(t *testing.T) {
+		c := NewMockClient(t, ServeJSON(t, http.MethodPost, "/v2/customer/partner-referrals", 201, `{
+			"links": [
+				{
+					"href": "https://api-m.sandbox.paypal.com/v2/customer/partner-referrals/ZjcyODU4ZWYtYTA1OC00ODIwLTk2M2EtOTZkZWQ4NmQwYzI3RU12cE5xa0xMRmk1NWxFSVJIT1JlTHdSZ2V6UjZWcW9RZjNlRmN5bzFFeW9GYm9ydm5nMTdvZVA2dGs9",
+					"rel": "self",
+					"method": "GET"
+				},
+				{
+					"href": "https://www.sandbox.paypal.com/bizsignup/partner/entry?referralToken=ZjcyODU4ZWYtYTA1OC00ODIwLTk2M2EtOTZkZWQ4NmQwYzI3RU12cE5xa0xMRmk1NWxFSVJIT1JlTHdSZ2V6UjZWcW9RZjNlRmN5bzFFeW9GYm9ydm5nMTdvZVA2dGs9",
+					"rel": "action_url",
+					"method": "GET"
+				}
+			]
+		}`))
+		req := &CreatePartnerReferralReq{
+			PartnerReferral: &PartnerReferral{
+				TrackingID: "seller-1",
+				Operations: []*ReferralOperationDetails{
+					{
+						Operation: ROAPIIntegration,
+						APIIntegrationPreference: &APIIntegrationPreference{
+							RESTAPIIntegration: &RESTAPIIntegration{
+								IntegrationMethod: "PAYPAL",
+								IntegrationType:   "THIRD_PARTY",
+								ThirdPartyDetails: &ThirdPartyDetails{
+									Features: []IntegrationFeature{IFPayment, IFRefund},
+								},
+							},
+						},
+					},
+				},
+				Products:      []ReferralProduct{RPExpressCheckout},
+				LegalConsents: []*LegalConsent{{Type: "SHARE_DATA_CONSENT", Granted: true}},
+			},
+		}
+		ptesting.R(c.CreatePartnerReferral(ctx, req)).NoError(t).
+			Do(func(t *testing.T, it *CreatedPartnerReferral) {
+				assert.Contains(t, it.ActionURL(), "/bizsignup/partner/entry?referralToken=")
+				assert.Equal(t, "ZjcyODU4ZWYtYTA1OC00ODIwLTk2M2EtOTZkZWQ4NmQwYzI3RU12cE5xa0xMRmk1NWxFSVJIT1JlTHdSZ2V6UjZWcW9RZjNlRmN5bzFFeW9GYm9ydm5nMTdvZVA2dGs9", it.ReferralID())
+			})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		c := NewMockClient(t, ServeJSON(t, http.MethodGet, "/v2/customer/partner-referrals/ID1", 200, `{
+			"partner_referral_id": "ID1",
+			"submitter_payer_id": "RFYUH2QQDGUQU",
+			"referral_data": {
+				"tracking_id": "seller-1",
+				"operations": [{"operation": "API_INTEGRATION"}],
+				"products": ["EXPRESS_CHECKOUT"],
+				"legal_consents": [{"type": "SHARE_DATA_CONSENT", "granted": true}]
+			}
+		}`))
+		ptesting.R(c.GetPartnerReferral(ctx, &GetPartnerReferralReq{ID: "ID1"})).NoError(t).
+			Do(func(t *testing.T, it *PartnerReferralData) {
+				assert.Equal(t, "RFYUH2QQDGUQU", it.SubmitterPayerID)
+				assert.Equal(t, "seller-1", it.ReferralData.TrackingID)
+				assert.Equal(t, ROAPIIntegration, it.ReferralData.Operations[0].Operation)
+			})
+	})
+}
+
+func TestMerchantIntegration(t *testing.T) {
+	ctx := context.Background()
+	path := "/v1/customer/partners/PARTNER/merchant-integrations/MERCHANT"
+	c := NewMockClient(t, ServeJSON(t, http.MethodGet, path, 200, `{
+		"merchant_id": "MERCHANT",
+		"tracking_id": "seller-1",
+		"products": [
+			{
+				"name": "EXPRESS_CHECKOUT",
+				"vetting_status": "SUBSCRIBED",
+				"capabilities": ["ACCEPT_DONATIONS"]
+			}
+		],
+		"capabilities": [{"name": "ACCEPT_DONATIONS", "status": "ACTIVE"}],
+		"payments_receivable": true,
+		"primary_email_confirmed": true,
+		"oauth_integrations": [
+			{
+				"integration_type": "OAUTH_THIRD_PARTY",
+				"integration_method": "PAYPAL",
+				"oauth_third_party": [
+					{
+						"partner_client_id": "AZ",
+						"merchant_client_id": "AX",
+						"scopes": ["https://uri.paypal.com/services/payments/realtimepayment"]
+					}
+				]
+			}
+		]
+	}`))
+	req := &GetMerchantIntegrationReq{PartnerID: "PARTNER", MerchantID: "MERCHANT"}
+	ptesting.R(c.GetMerchantIntegration(ctx, req)).NoError(t).
+		Do(func(t *testing.T, it *MerchantIntegration) {
+			assert.True(t, it.CanReceivePayments())
+			assert.Equal(t, VSSubscribed, it.Products[0].VettingStatus)
+			assert.Equal(t, CSActive, it.Capability("ACCEPT_DONATIONS").Status)
+			assert.Nil(t, it.Capability("CUSTOM_CARD_PROCESSING"))
+
+			it.PrimaryEmailConfirmed = false
+			assert.False(t, it.CanReceivePayments())
+		})
+
+	t.Run("Find", func(t *testing.T) {
+		path := "/v1/customer/partners/PARTNER/merchant-integrations?tracking_id=seller-1"
+		c := NewMockClient(t, ServeJSON(t, http.MethodGet, path, 200, `{
+			"merchant_id": "MERCHANT",
+			"tracking_id": "seller-1"
+		}`))
+		req := &FindMerchantIntegrationReq{PartnerID: "PARTNER", TrackingID: "seller-1"}
+		ptesting.R(c.FindMerchantIntegration(ctx, req)).NoError(t).
+			Do(func(t *testing.T, it *MerchantIntegration) {
+				assert.Equal(t, "MERCHANT", it.MerchantID)
+			})
+	})
+}
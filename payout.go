@@ -0,0 +1,45 @@
+package paypal
+
+import "time"
+
+// PayoutBatchHeader is the header of a payout batch.
+type PayoutBatchHeader struct {
+	PayoutBatchID string    `json:"payout_batch_id,omitempty"`
+	BatchStatus   string    `json:"batch_status,omitempty"`
+	TimeCreated   time.Time `json:"time_created,omitempty"`
+	TimeCompleted time.Time `json:"time_completed,omitempty"`
+	Amount        *Amount   `json:"amount,omitempty"`
+	Fees          *Amount   `json:"fees,omitempty"`
+}
+
+// PayoutBatch is a batch of payouts.
+//
+// See https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_get.
+type PayoutBatch struct {
+	BatchHeader *PayoutBatchHeader `json:"batch_header,omitempty"`
+	Items       []*PayoutItem      `json:"items,omitempty"`
+	Links       []*Link            `json:"links,omitempty"`
+}
+
+// Payout is the details of a payout to a receiver.
+type Payout struct {
+	RecipientType string  `json:"recipient_type,omitempty"`
+	Amount        *Amount `json:"amount,omitempty"`
+	Note          string  `json:"note,omitempty"`
+	Receiver      string  `json:"receiver,omitempty"`
+	SenderItemID  string  `json:"sender_item_id,omitempty"`
+}
+
+// PayoutItem is an item of a payout batch.
+//
+// See https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get.
+type PayoutItem struct {
+	PayoutItemID      string    `json:"payout_item_id,omitempty"`
+	TransactionID     string    `json:"transaction_id,omitempty"`
+	TransactionStatus string    `json:"transaction_status,omitempty"`
+	PayoutBatchID     string    `json:"payout_batch_id,omitempty"`
+	PayoutItemFee     *Amount   `json:"payout_item_fee,omitempty"`
+	PayoutItem        *Payout   `json:"payout_item,omitempty"`
+	TimeProcessed     time.Time `json:"time_processed,omitempty"`
+	Links             []*Link   `json:"links,omitempty"`
+}
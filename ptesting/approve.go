@@ -0,0 +1,140 @@
+package ptesting
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ApproveEnv is the environment variable to approve on PayPal by hand in [Approver.Approve]:
+// the approve links are logged, and the tests wait for the buyer to approve them, e.g.
+//
+//	PTESTING_APPROVE=1 go test -v -run TestContractSubscription -timeout 10m
+const ApproveEnv = "PTESTING_APPROVE"
+
+// The sandbox test card, which approves the orders created with it as the payment source
+// without a buyer, so that the capture is tested on PayPal without [Approver].
+//
+// See https://developer.paypal.com/tools/sandbox/card-testing/.
+const (
+	TestCardNumber       = "4111111111111111"
+	TestCardSecurityCode = "123"
+)
+
+// TestCardExpiry is the expiry of the test card in the format of YYYY-MM, which is 3 years later.
+var TestCardExpiry = time.Now().AddDate(3, 0, 0).Format("2006-01")
+
+// ApprovedFunc reports whether the order or the subscription has been approved,
+// e.g. by its status from paypal.Client.GetSubscription.
+type ApprovedFunc func(ctx context.Context) (ok bool, err error)
+
+// Approver approves subscriptions as a buyer, so that the activation can be tested.
+//
+// Only the fake server is approved by a script. There is no scripted approval of the subscriptions
+// on the sandbox, as the subscriber approval of PayPal needs a buyer to log in,
+// so the sandbox coverage of the activation is manual only:
+// approve by hand with [ApproveEnv], or provide Browse to drive a browser.
+// The orders need no Approver on the sandbox, create them with the test card instead,
+// see [TestCardNumber].
+type Approver struct {
+	// Fake is the fake server whose approve links are visited to approve immediately.
+	Fake *Server
+	// Browse approves the link on PayPal as a buyer if not nil,
+	// e.g. by driving a headless browser to log in with a sandbox personal account.
+	Browse func(ctx context.Context, link string) error
+	// Timeout limits the waiting for the approval on PayPal, 5 minutes if zero.
+	Timeout time.Duration
+	// Interval is the interval to check the approval on PayPal, 2 seconds if zero.
+	Interval time.Duration
+}
+
+// NewApprover returns a new [Approver] approving immediately on the fake server,
+// which is nil if the tests run against PayPal.
+func NewApprover(fake *Server) *Approver {
+	return &Approver{Fake: fake}
+}
+
+// Approve approves the subscription, or the order on the fake server, of the approve link,
+// and fails the test if it is not approved.
+//
+// The link is visited on the fake server.
+// On PayPal, it is browsed by Browse, or by hand if [ApproveEnv] is set,
+// and then approved is checked until true;
+// the test is skipped if neither is available.
+func (a *Approver) Approve(t testing.TB, link string, approved ApprovedFunc) {
+	t.Helper()
+	ctx := context.Background()
+	if a.Fake != nil && strings.HasPrefix(link, a.Fake.URL+"/") {
+		if err := visit(ctx, a.Fake.Client(), link); err != nil {
+			t.Fatalf("approve: %v", err)
+		}
+		return
+	}
+
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	switch {
+	case a.Browse != nil:
+		if err := a.Browse(ctx, link); err != nil {
+			t.Fatalf("browse: %v", err)
+		}
+	case os.Getenv(ApproveEnv) != "":
+		t.Logf("Approve in %s as a buyer: %s", timeout, link)
+	default:
+		t.Skipf("approval of subscriptions on PayPal is manual only, set %s=1 to approve by hand: %s",
+			ApproveEnv, link)
+	}
+	if err := a.wait(ctx, approved); err != nil {
+		t.Fatalf("approve %s: %v", link, err)
+	}
+}
+
+// wait checks the approval until approved or ctx is done.
+func (a *Approver) wait(ctx context.Context, approved ApprovedFunc) error {
+	interval := a.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ok, err := approved(ctx)
+		if err != nil {
+			return fmt.Errorf("check: %w", err)
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("not approved: %w", ctx.Err())
+		}
+	}
+}
+
+func visit(ctx context.Context, hc *http.Client, link string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		bs, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		return fmt.Errorf("status %d: %s", res.StatusCode, strings.TrimSpace(string(bs)))
+	}
+	return nil
+}
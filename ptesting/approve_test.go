@@ -0,0 +1,37 @@
+package ptesting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestApprover(t *testing.T) {
+	link := "https://www.sandbox.paypal.com/webapps/billing/subscriptions?ba_token=BA-2M539689T3856352J"
+	checks := 0
+	approved := func(ctx context.Context) (bool, error) {
+		checks++
+		return checks == 3, nil
+	}
+
+	a := ptesting.NewApprover(nil)
+	a.Interval = time.Millisecond
+	a.Browse = func(ctx context.Context, l string) error {
+		assert.Equal(t, link, l)
+		return nil
+	}
+	a.Approve(t, link, approved)
+	assert.Equal(t, 3, checks)
+
+	t.Setenv(ptesting.ApproveEnv, "")
+	var inner *testing.T
+	t.Run("Skip", func(t *testing.T) {
+		inner = t
+		ptesting.NewApprover(nil).Approve(t, link, approved)
+	})
+	assert.True(t, inner.Skipped())
+}
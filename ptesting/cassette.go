@@ -0,0 +1,275 @@
+package ptesting
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordEnv is the environment variable to make [NewCassette] record instead of replay,
+// e.g. PTESTING_RECORD=1 go test ./...
+const RecordEnv = "PTESTING_RECORD"
+
+// ErrNoInteraction indicates no recorded interaction matches the request.
+var ErrNoInteraction = errors.New("no matching interaction in cassette")
+
+// Redacted replaces the secrets in the cassettes.
+const Redacted = "REDACTED"
+
+// redactedFields are the JSON and form fields redacted in the bodies.
+var redactedFields = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_token":  true,
+	"client_secret": true,
+	"code":          true,
+	"nonce":         true,
+}
+
+// keptHeaders are the response headers kept in the cassettes.
+var keptHeaders = []string{"Content-Type", "Paypal-Debug-Id"}
+
+// Interaction is a recorded HTTP request and its response.
+// The Authorization header is never recorded.
+type Interaction struct {
+	Method string `json:"method"`
+	// URI is the path with the query.
+	URI string `json:"uri"`
+	// Body is the normalized and redacted request body.
+	Body string `json:"body,omitempty"`
+
+	Status         int         `json:"status"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+}
+
+// Cassette is an [http.RoundTripper] which records the interactions
+// with the next round tripper into a JSONL file, or replays them from the file.
+//
+// The requests are matched by the method, the path with the query and the normalized body,
+// in the recorded order if there are identical requests.
+// The secrets are redacted: the Authorization header is dropped,
+// and the token fields in the bodies are replaced with [Redacted].
+type Cassette struct {
+	path string
+	// next is the round tripper to record, nil for replaying.
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// NewCassette returns a cassette replaying the file, and fails the test if the file is missing.
+// It records the interactions with [http.DefaultTransport] into the file instead
+// if the [RecordEnv] environment variable is set, and saves the file when the test ends.
+//
+// A client uses the cassette with paypal.Client.SetTransport.
+func NewCassette(t testing.TB, path string) *Cassette {
+	t.Helper()
+	if os.Getenv(RecordEnv) != "" {
+		c := RecordCassette(path, http.DefaultTransport)
+		t.Cleanup(func() {
+			if err := c.Save(); err != nil {
+				t.Errorf("save cassette: %v", err)
+			}
+		})
+		return c
+	}
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("load cassette: %v, set %s=1 to record", err, RecordEnv)
+	}
+	t.Cleanup(func() {
+		if n := c.Unused(); n > 0 {
+			t.Errorf("%d interactions in cassette %s were not replayed", n, path)
+		}
+	})
+	return c
+}
+
+// RecordCassette returns a cassette recording the interactions with the next round tripper,
+// call [Cassette.Save] to write them into the file.
+func RecordCassette(path string, next http.RoundTripper) *Cassette {
+	return &Cassette{path: path, next: next}
+}
+
+// LoadCassette returns a cassette replaying the interactions in the file.
+func LoadCassette(path string) (res *Cassette, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	res = &Cassette{path: path}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		it := new(Interaction)
+		if err = json.Unmarshal(sc.Bytes(), it); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		res.interactions = append(res.interactions, it)
+	}
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	res.used = make([]bool, len(res.interactions))
+	return
+}
+
+// Recording reports whether the cassette is recording.
+func (c *Cassette) Recording() bool {
+	return c.next != nil
+}
+
+// Unused returns the number of the interactions not replayed yet.
+func (c *Cassette) Unused() (n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, used := range c.used {
+		if !used {
+			n++
+		}
+	}
+	return
+}
+
+// Save writes the recorded interactions into the file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	for _, it := range c.interactions {
+		if err := enc.Encode(it); err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+	}
+	return os.WriteFile(c.path, buf.Bytes(), 0o644)
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	var body []byte
+	if req.Body != nil {
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	key := &Interaction{
+		Method: req.Method,
+		URI:    req.URL.RequestURI(),
+		Body:   normalizeBody(req.Header.Get("Content-Type"), body),
+	}
+	if c.Recording() {
+		return c.record(req, key)
+	}
+	return c.replay(req, key)
+}
+
+func (c *Cassette) record(req *http.Request, it *Interaction) (res *http.Response, err error) {
+	if res, err = c.next.RoundTrip(req); err != nil {
+		return
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	it.Status = res.StatusCode
+	it.ResponseHeader = http.Header{}
+	for _, k := range keptHeaders {
+		if v := res.Header.Values(k); len(v) > 0 {
+			it.ResponseHeader[k] = v
+		}
+	}
+	it.ResponseBody = normalizeBody(res.Header.Get("Content-Type"), body)
+	c.mu.Lock()
+	c.interactions = append(c.interactions, it)
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+	return
+}
+
+func (c *Cassette) replay(req *http.Request, key *Interaction) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, it := range c.interactions {
+		if c.used[i] || it.Method != key.Method || it.URI != key.URI || it.Body != key.Body {
+			continue
+		}
+		c.used[i] = true
+		res := newResponse(req, it.Status, "", it.ResponseBody)
+		res.Header = it.ResponseHeader.Clone()
+		if res.Header == nil {
+			res.Header = http.Header{}
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("%w %s: %s %s %s", ErrNoInteraction, c.path, key.Method, key.URI, key.Body)
+}
+
+// normalizeBody returns the body with the JSON keys sorted and the secrets redacted.
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return string(body)
+		}
+		for k := range form {
+			if redactedFields[k] {
+				form.Set(k, Redacted)
+			}
+		}
+		return form.Encode()
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	bs, err := json.Marshal(redact(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(bs)
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, vv := range v {
+			if redactedFields[k] {
+				v[k] = Redacted
+			} else {
+				v[k] = redact(vv)
+			}
+		}
+	case []any:
+		for i, vv := range v {
+			v[i] = redact(vv)
+		}
+	}
+	return v
+}
@@ -0,0 +1,78 @@
+package ptesting_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal"
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestCassette(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "order.jsonl")
+	s := ptesting.NewServer()
+	defer s.Close()
+
+	// Record
+	rec := ptesting.RecordCassette(path, http.DefaultTransport)
+	assert.True(t, rec.Recording())
+	c := paypal.NewClient(s.URL, "id", "top-secret")
+	c.SetTransport(rec)
+	order := ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t).V()
+	require.NoError(t, s.ApproveOrder(order.ID))
+	captured := ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).NoError(t).V()
+	var e *paypal.Error
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).ErrorAs(t, &e)
+	require.NoError(t, rec.Save())
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(bs), "top-secret")
+	assert.NotContains(t, string(bs), "A21AA")
+	assert.NotContains(t, string(bs), "Authorization")
+	assert.Contains(t, string(bs), `access_token\":\"REDACTED`)
+	assert.Contains(t, string(bs), `"uri":"/v2/checkout/orders"`)
+	assert.Contains(t, string(bs), `"body":"grant_type=client_credentials"`)
+
+	// Replay without the server, the body is matched regardless of the key order
+	s.Close()
+	cas, err := ptesting.LoadCassette(path)
+	require.NoError(t, err)
+	assert.False(t, cas.Recording())
+	assert.Equal(t, 4, cas.Unused())
+	c = paypal.NewClient(s.URL, "id", "other-secret")
+	c.SetTransport(cas)
+	order2 := ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: &paypal.Order{
+		PurchaseUnits: newOrder().PurchaseUnits,
+		Intent:        paypal.OICapture,
+	}})).NoError(t).V()
+	assert.Equal(t, order.ID, order2.ID)
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).NoError(t).
+		Do(func(t *testing.T, it *paypal.Order) {
+			assert.Equal(t, captured.Captures()[0].ID, it.Captures()[0].ID)
+		})
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).ErrorAs(t, &e)
+	assert.Equal(t, "ORDER_ALREADY_CAPTURED", e.Details[0].Issue)
+	assert.Zero(t, cas.Unused())
+
+	// Unmatched
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).
+		ErrorIs(t, ptesting.ErrNoInteraction)
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: "OTHER"})).
+		ErrorContains(t, "POST /v2/checkout/orders/OTHER/capture")
+}
+
+func TestNewCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0o644))
+	c := ptesting.NewCassette(t, path)
+	assert.False(t, c.Recording())
+	assert.Zero(t, c.Unused())
+}
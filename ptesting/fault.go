@@ -0,0 +1,196 @@
+package ptesting
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/adobaai/paypal/internal/operation"
+)
+
+// AnyOperation matches all operations in [FaultTransport.On] and [FaultTransport.Always].
+const AnyOperation = "*"
+
+// Fault handles a request instead of or around the next round tripper.
+type Fault func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// FaultTransport is an [http.RoundTripper] injecting faults into the requests
+// by the operation names, e.g. "CaptureOrder", set by paypal.WithOperation,
+// or else matched by the method and the path in paypal.Operations.
+// The token requests are of the "Auth" operation.
+//
+// For example, to fail the first two captures and then succeed:
+//
+//	ft := ptesting.NewFaultTransport(nil)
+//	ft.On("CaptureOrder", ptesting.ServerError(503), ptesting.ServerError(503))
+//	client.SetTransport(ft)
+type FaultTransport struct {
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	scripts map[string][]Fault
+	always  map[string]Fault
+	calls   map[string]int
+}
+
+// NewFaultTransport returns a new [FaultTransport] passing the requests to the next,
+// [http.DefaultTransport] is used if nil.
+func NewFaultTransport(next http.RoundTripper) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &FaultTransport{
+		next:    next,
+		scripts: map[string][]Fault{},
+		always:  map[string]Fault{},
+		calls:   map[string]int{},
+	}
+}
+
+// On scripts the following calls of the operation: the n-th call is handled by the n-th fault,
+// a nil fault passes the call through, and so do the calls after the script.
+// The script of the operation takes precedence over the one of [AnyOperation].
+func (t *FaultTransport) On(op string, faults ...Fault) *FaultTransport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scripts[op] = append(t.scripts[op], faults...)
+	return t
+}
+
+// Always handles all calls of the operation not handled by the scripts with the fault.
+func (t *FaultTransport) Always(op string, f Fault) *FaultTransport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.always[op] = f
+	return t
+}
+
+// Calls returns the number of the calls of the operation, including the failed ones.
+func (t *FaultTransport) Calls(op string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls[op]
+}
+
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var name string
+	if op, ok := operation.FromRequest(req); ok {
+		name = op.Name
+	}
+	if f := t.fault(name); f != nil {
+		return f(req, t.next)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// fault returns the fault for the next call of the operation, nil to pass it through.
+func (t *FaultTransport) fault(op string) Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls[op]++
+	t.calls[AnyOperation]++
+	for _, key := range []string{op, AnyOperation} {
+		if script := t.scripts[key]; len(script) > 0 {
+			t.scripts[key] = script[1:]
+			return script[0]
+		}
+	}
+	if f, ok := t.always[op]; ok {
+		return f
+	}
+	return t.always[AnyOperation]
+}
+
+// Latency delays the request by d, or until the request is canceled.
+func Latency(d time.Duration) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return next.RoundTrip(req)
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// ConnReset fails the request with a connection reset error without sending it.
+func ConnReset() Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		return nil, &net.OpError{
+			Op:  "read",
+			Net: "tcp",
+			Err: os.NewSyscallError("read", syscall.ECONNRESET),
+		}
+	}
+}
+
+// TooManyRequests responds with 429 and the Retry-After header in seconds.
+func TooManyRequests(retryAfter time.Duration) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		res := newResponse(req, http.StatusTooManyRequests, "application/json", fmt.Sprintf(
+			`{"name":"RATE_LIMIT_REACHED","message":"Too many requests. Blocked due to rate limiting.","debug_id":"%s"}`,
+			newID(13)))
+		res.Header.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		return res, nil
+	}
+}
+
+// ServerError responds with the status and an HTML body as the PayPal edge servers do.
+func ServerError(status int) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		body := fmt.Sprintf("<html><head><title>%d %s</title></head><body><h1>%[2]s</h1></body></html>",
+			status, http.StatusText(status))
+		return newResponse(req, status, "text/html", body), nil
+	}
+}
+
+// TruncatedJSON passes the request through and truncates the response body by half.
+func TruncatedJSON() Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		res, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		bs, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		bs = bs[:len(bs)/2]
+		res.Body = io.NopCloser(strings.NewReader(string(bs)))
+		res.ContentLength = int64(len(bs))
+		res.Header.Del("Content-Length")
+		return res, nil
+	}
+}
+
+// TokenExpired responds with 401 as if the access token expired mid-flight.
+func TokenExpired() Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		return newResponse(req, http.StatusUnauthorized, "application/json",
+			`{"error":"invalid_token","error_description":"Access Token not found in cache"}`), nil
+	}
+}
+
+func newResponse(req *http.Request, status int, contentType, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
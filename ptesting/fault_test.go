@@ -0,0 +1,128 @@
+package ptesting_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal"
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func newFaultClient(t *testing.T) (*paypal.Client, *ptesting.FaultTransport, *ptesting.Server) {
+	s := ptesting.NewServer()
+	t.Cleanup(s.Close)
+	ft := ptesting.NewFaultTransport(nil)
+	c := paypal.NewClient(s.URL, "id", "secret")
+	c.SetTransport(ft)
+	return c, ft, s
+}
+
+func TestFaultTransportScript(t *testing.T) {
+	ctx := context.Background()
+	c, ft, s := newFaultClient(t)
+	order := ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t).V()
+	require.NoError(t, s.ApproveOrder(order.ID))
+
+	ft.On("CaptureOrder", ptesting.ServerError(503), ptesting.ConnReset())
+	req := &paypal.CaptureOrderReq{ID: order.ID}
+	var e *paypal.Error
+	ptesting.R(c.CaptureOrder(ctx, req)).ErrorAs(t, &e)
+	assert.Equal(t, http.StatusServiceUnavailable, e.StatusCode)
+	assert.Contains(t, e.Message, "503 Service Unavailable")
+	ptesting.R(c.CaptureOrder(ctx, req)).ErrorIs(t, syscall.ECONNRESET)
+	ptesting.R(c.CaptureOrder(ctx, req)).NoError(t).Do(func(t *testing.T, it *paypal.Order) {
+		assert.Equal(t, paypal.OSCompleted, it.Status)
+	})
+	assert.Equal(t, 3, ft.Calls("CaptureOrder"))
+	assert.Equal(t, 1, ft.Calls("CreateOrder"))
+	assert.Equal(t, 1, ft.Calls("Auth"))
+	assert.Equal(t, 5, ft.Calls(ptesting.AnyOperation))
+}
+
+func TestFaultTransportFaults(t *testing.T) {
+	ctx := context.Background()
+	c, ft, _ := newFaultClient(t)
+	create := func() (*paypal.Order, error) {
+		return c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})
+	}
+
+	ft.On("CreateOrder", ptesting.TooManyRequests(30*time.Second), ptesting.TruncatedJSON(), nil)
+	var e *paypal.Error
+	ptesting.R(create()).ErrorAs(t, &e)
+	assert.Equal(t, 429, e.StatusCode)
+	assert.Equal(t, "RATE_LIMIT_REACHED", e.Name)
+	ptesting.R(create()).ErrorContains(t, "unexpected end of JSON input")
+	ptesting.R(create()).NoError(t)
+
+	ft.Always(ptesting.AnyOperation, ptesting.Latency(time.Second))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).
+		ErrorIs(t, context.DeadlineExceeded)
+}
+
+func TestFaultTransportTokenExpired(t *testing.T) {
+	ctx := context.Background()
+	c, ft, s := newFaultClient(t)
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t)
+
+	// The client refreshes the token and retries once
+	ft.On("CreateOrder", ptesting.TokenExpired())
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t).
+		Do(func(t *testing.T, it *paypal.Order) {
+			assert.Equal(t, paypal.OSCreated, it.Status)
+		})
+	assert.Equal(t, 2, ft.Calls("Auth"))
+	assert.Equal(t, 3, ft.Calls("CreateOrder"))
+
+	s.ExpireTokens()
+	ptesting.R(c.GetSubscription(ctx, &paypal.GetSubscriptionReq{ID: "I-1"})).ErrorContains(t, "RESOURCE_NOT_FOUND")
+	assert.Equal(t, 3, ft.Calls("Auth"))
+
+	// No more retries
+	ft.On("GetSubscription", ptesting.TokenExpired(), ptesting.TokenExpired())
+	var e *paypal.Error
+	ptesting.R(c.GetSubscription(ctx, &paypal.GetSubscriptionReq{ID: "I-1"})).ErrorAs(t, &e)
+	assert.Equal(t, http.StatusUnauthorized, e.StatusCode)
+	assert.Equal(t, "invalid_token", e.Err)
+	assert.Equal(t, 4, ft.Calls("Auth"))
+
+	ft.On("CancelSubscription", ptesting.TokenExpired())
+	err := c.CancelSubscription(ctx, &paypal.CancelSubscriptionReq{ID: "I-1", Reason: "test"})
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusNotFound, e.StatusCode)
+	assert.Equal(t, 5, ft.Calls("Auth"))
+}
+
+func TestFaultTransportConcurrent(t *testing.T) {
+	ctx := context.Background()
+	c, ft, s := newFaultClient(t)
+	createAll := func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t)
+			}()
+		}
+		wg.Wait()
+	}
+
+	// The requests wait for a single token
+	createAll()
+	assert.Equal(t, 1, ft.Calls("Auth"))
+
+	// The token rejected by all the requests is refreshed once
+	s.ExpireTokens()
+	createAll()
+	assert.Equal(t, 2, ft.Calls("Auth"))
+	assert.Equal(t, 8*3, ft.Calls("CreateOrder"))
+}
@@ -1,12 +1,36 @@
 package ptesting
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
 )
 
+// Update makes [Result.Golden] write the golden files instead of comparing with them.
+// It is set by the -ptesting.update flag, which is prefixed
+// so as not to clash with the -update flag of the tests using ptesting,
+// and can also be set from the flag of the tests, e.g. in TestMain.
+var Update bool
+
+func init() {
+	flag.BoolVar(&Update, "ptesting.update", false, "update the golden files of ptesting")
+}
+
+// VolatileFields are the JSON fields which usually differ between the responses,
+// to be ignored in [Result.JSONEq] and [Result.Golden].
+var VolatileFields = []string{"id", "create_time", "update_time", "debug_id", "links"}
+
 // Result provides some useful methods to write concise code in tests.
+//
+// The methods without a t argument, e.g. [Result.Equal], use the t
+// of the last method with one, e.g. [Result.NoError], and panic if there is none;
+// use the variants with a t, e.g. [Result.EqualT], to start with them.
 type Result[T any] struct {
 	t   *testing.T
 	v   T
@@ -24,6 +48,13 @@ func (r *Result[T]) V() T {
 	return r.v
 }
 
+func (r *Result[T]) mustT() *testing.T {
+	if r.t == nil {
+		panic("ptesting: no t, call NoError, EqualT or another method with t first")
+	}
+	return r.t
+}
+
 func (r *Result[T]) NoError(t *testing.T, msgf ...any) *Result[T] {
 	require.NoError(t, r.err, msgf...)
 	r.t = t
@@ -48,17 +79,111 @@ func (r *Result[T]) ErrorContains(t *testing.T, s string, msgf ...any) *Result[T
 	return r
 }
 
+// ErrorIsPayPal asserts the error is a *paypal.Error with the issue in its details
+// or of the name, see paypal.Error.HasIssue.
+func (r *Result[T]) ErrorIsPayPal(t *testing.T, issue string, msgf ...any) *Result[T] {
+	t.Helper()
+	var e interface{ HasIssue(issue string) bool }
+	require.ErrorAs(t, r.err, &e, msgf...)
+	require.Truef(t, e.HasIssue(issue), "error %q does not have issue %s", r.err, issue)
+	r.t = t
+	return r
+}
+
 func (r *Result[T]) Equal(v T, msgf ...any) *Result[T] {
-	require.Equal(r.t, v, r.v, msgf...)
+	return r.EqualT(r.mustT(), v, msgf...)
+}
+
+// EqualT is [Result.Equal] with the t, which is used by the later methods without one.
+func (r *Result[T]) EqualT(t *testing.T, v T, msgf ...any) *Result[T] {
+	require.Equal(t, v, r.v, msgf...)
+	r.t = t
 	return r
 }
 
 func (r *Result[T]) EqualError(t *testing.T, errStr string, msgf ...any) *Result[T] {
 	require.EqualError(t, r.err, errStr, msgf...)
+	r.t = t
 	return r
 }
 
+// JSONEq asserts the value in JSON format is equivalent to the expected JSON,
+// with the ignored fields at any depth, e.g. [VolatileFields], removed from both before comparing.
+func (r *Result[T]) JSONEq(expected string, ignored ...string) *Result[T] {
+	t := r.mustT()
+	t.Helper()
+	var ev any
+	require.NoError(t, json.Unmarshal([]byte(expected), &ev), "unmarshal expected")
+	require.JSONEq(t, mustJSON(t, ignore(ev, ignored)), mustJSON(t, r.jsonValue(t, ignored)))
+	return r
+}
+
+// Golden asserts the value in JSON format is equivalent to the golden file testdata/<name>.json,
+// with the ignored fields as in [Result.JSONEq].
+// The golden file is written instead if [Update] is set, e.g. go test ./... -ptesting.update.
+func (r *Result[T]) Golden(name string, ignored ...string) *Result[T] {
+	t := r.mustT()
+	t.Helper()
+	path := filepath.Join("testdata", name+".json")
+	if Update {
+		bs, err := json.MarshalIndent(r.jsonValue(t, ignored), "", "  ")
+		require.NoError(t, err, "marshal")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, append(bs, '\n'), 0o644))
+		return r
+	}
+	bs, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("golden file %s not found, run the test with -ptesting.update to create it", path)
+	}
+	require.NoError(t, err)
+	return r.JSONEq(string(bs), ignored...)
+}
+
+// jsonValue returns the value as a JSON value without the ignored fields.
+func (r *Result[T]) jsonValue(t *testing.T, ignored []string) any {
+	bs, err := json.Marshal(r.v)
+	require.NoError(t, err, "marshal")
+	var v any
+	require.NoError(t, json.Unmarshal(bs, &v), "unmarshal")
+	return ignore(v, ignored)
+}
+
 func (r *Result[T]) Do(f func(t *testing.T, it T)) *Result[T] {
-	f(r.t, r.v)
+	return r.DoT(r.mustT(), f)
+}
+
+// DoT is [Result.Do] with the t, which is used by the later methods without one.
+func (r *Result[T]) DoT(t *testing.T, f func(t *testing.T, it T)) *Result[T] {
+	f(t, r.v)
+	r.t = t
 	return r
 }
+
+// ignore removes the fields from the JSON value.
+func ignore(v any, fields []string) any {
+	if len(fields) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for k, vv := range v {
+			if slices.Contains(fields, k) {
+				delete(v, k)
+			} else {
+				v[k] = ignore(vv, fields)
+			}
+		}
+	case []any:
+		for i, vv := range v {
+			v[i] = ignore(vv, fields)
+		}
+	}
+	return v
+}
+
+func mustJSON(t *testing.T, v any) string {
+	bs, err := json.Marshal(v)
+	require.NoError(t, err, "marshal")
+	return string(bs)
+}
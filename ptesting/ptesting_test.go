@@ -0,0 +1,48 @@
+package ptesting_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adobaai/paypal"
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestResult(t *testing.T) {
+	ctx := context.Background()
+	s := ptesting.NewServer()
+	defer s.Close()
+	c := paypal.NewClient(s.URL, "id", "secret")
+
+	req := &paypal.CreateOrderReq{Order: newOrder(), Prefer: "return=representation"}
+	ptesting.R(c.CreateOrder(ctx, req)).NoError(t).
+		JSONEq(`{
+			"intent": "CAPTURE",
+			"status": "CREATED",
+			"purchase_units": [{"reference_id": "default", "amount": {"currency_code": "USD", "value": "12.12"}}]
+		}`, ptesting.VolatileFields...).
+		Golden("order", ptesting.VolatileFields...)
+
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: "NOT-FOUND"})).
+		ErrorIsPayPal(t, "RESOURCE_NOT_FOUND").
+		ErrorIsPayPal(t, "INVALID_RESOURCE_ID")
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: &paypal.Order{}})).
+		ErrorIsPayPal(t, "INVALID_REQUEST")
+
+	ptesting.R(1, errors.New("failed")).EqualError(t, "failed").Equal(1)
+	assert.PanicsWithValue(t, "ptesting: no t, call NoError, EqualT or another method with t first", func() {
+		ptesting.R(1, nil).Equal(1)
+	})
+	ptesting.R(1, nil).EqualT(t, 1).Equal(1)
+	ptesting.R("a", nil).DoT(t, func(t *testing.T, it string) {
+		assert.Equal(t, "a", it)
+	}).Equal("a")
+
+	// The flag is prefixed so as not to clash with the -update flag of the tests using ptesting
+	assert.NotNil(t, flag.Lookup("ptesting.update"))
+	assert.Nil(t, flag.Lookup("update"))
+}
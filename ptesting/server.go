@@ -0,0 +1,654 @@
+package ptesting
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobaai/paypal/internal/operation"
+)
+
+// Server is a stateful in-memory fake of the PayPal REST API for tests,
+// pass the URL to paypal.NewClient as the base URL.
+//
+// It implements:
+//
+//   - OAuth tokens: POST /v1/oauth2/token, also with the ID tokens,
+//     and the client tokens: POST /v1/identity/generate-token
+//   - Orders: POST /v2/checkout/orders, GET /v2/checkout/orders/{id}
+//     and POST /v2/checkout/orders/{id}/capture
+//   - Subscriptions: POST /v1/billing/subscriptions, GET /v1/billing/subscriptions/{id}
+//     and POST /v1/billing/subscriptions/{id}/cancel
+//   - Webhooks: POST /v1/notifications/verify-webhook-signature
+//     and POST /v1/notifications/simulate-event, which responds with the event without sending it
+//
+// The orders and the subscriptions are approved by [Server.ApproveOrder]
+// and [Server.ApproveSubscription], or by visiting their "approve" links.
+// The orders created with a card payment source are approved immediately, as on PayPal.
+// The orders are created and captured in the minimal representations of PayPal,
+// unless the "Prefer: return=representation" header is sent.
+// The errors are in the shape of PayPal errors.
+type Server struct {
+	*httptest.Server
+
+	// ClientID and ClientSecret are the accepted credentials,
+	// all non-empty credentials are accepted if empty.
+	ClientID, ClientSecret string
+	// TokenTTL is the lifetime of the issued tokens, 9 hours if zero.
+	TokenTTL time.Duration
+	// VerifyWebhookSign reports whether the signature is valid,
+	// all signatures with the matching webhook ID are valid if nil,
+	// or the ones signed by the Signer if it is not nil.
+	VerifyWebhookSign func(req *WebhookSignature) bool
+	// Signer verifies the signatures in the default VerifyWebhookSign if not nil,
+	// as PayPal verifies its own signatures.
+	Signer *WebhookSigner
+	// WebhookID is the webhook ID accepted by the default VerifyWebhookSign,
+	// all webhook IDs are accepted if empty.
+	WebhookID string
+	// Now returns the current time, [time.Now] is used if nil.
+	Now func() time.Time
+
+	mu            sync.Mutex
+	tokens        map[string]time.Time
+	orders        map[string]*fakeOrder
+	subscriptions map[string]*fakeSubscription
+}
+
+// WebhookSignature is the request of verifying a webhook signature.
+type WebhookSignature struct {
+	AuthAlgo         string          `json:"auth_algo"`
+	CertURL          string          `json:"cert_url"`
+	TransmissionID   string          `json:"transmission_id"`
+	TransmissionSig  string          `json:"transmission_sig"`
+	TransmissionTime string          `json:"transmission_time"`
+	WebhookID        string          `json:"webhook_id"`
+	WebhookEvent     json.RawMessage `json:"webhook_event"`
+}
+
+type fakeLink struct {
+	HRef   string `json:"href"`
+	Rel    string `json:"rel"`
+	Method string `json:"method"`
+}
+
+type fakeOrder struct {
+	ID            string           `json:"id"`
+	Intent        string           `json:"intent"`
+	Status        string           `json:"status"`
+	PurchaseUnits []map[string]any `json:"purchase_units"`
+	PaymentSource *fakeSource      `json:"payment_source,omitempty"`
+	CreateTime    time.Time        `json:"create_time"`
+	UpdateTime    time.Time        `json:"update_time"`
+	Links         []*fakeLink      `json:"links"`
+}
+
+type fakeSource struct {
+	Card *fakeCard `json:"card,omitempty"`
+}
+
+// fakeCard is the card of the requests and, without the number, the expiry and the security code,
+// the one of the responses.
+type fakeCard struct {
+	Name         string `json:"name,omitempty"`
+	Number       string `json:"number,omitempty"`
+	Expiry       string `json:"expiry,omitempty"`
+	SecurityCode string `json:"security_code,omitempty"`
+	LastDigits   string `json:"last_digits,omitempty"`
+	Brand        string `json:"brand,omitempty"`
+	Type         string `json:"type,omitempty"`
+}
+
+type fakeSubscription struct {
+	ID               string         `json:"id"`
+	PlanID           string         `json:"plan_id"`
+	Quantity         string         `json:"quantity,omitempty"`
+	Status           string         `json:"status"`
+	StatusUpdateTime time.Time      `json:"status_update_time"`
+	Plan             map[string]any `json:"plan,omitempty"`
+	CreateTime       time.Time      `json:"create_time"`
+	UpdateTime       time.Time      `json:"update_time"`
+	Links            []*fakeLink    `json:"links"`
+}
+
+// NewServer starts and returns a new [Server], which should be closed after use.
+func NewServer() *Server {
+	s := &Server{
+		tokens:        map[string]time.Time{},
+		orders:        map[string]*fakeOrder{},
+		subscriptions: map[string]*fakeSubscription{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// ExpireTokens expires all the issued tokens,
+// so that the following requests are rejected with 401.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+// ApproveOrder approves the order as if the payer approved it.
+func (s *Server) ApproveOrder(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[id]
+	if !ok {
+		return fmt.Errorf("order not found: %s", id)
+	}
+	if o.Status != "CREATED" && o.Status != "PAYER_ACTION_REQUIRED" {
+		return fmt.Errorf("order %s cannot be approved in status %s", id, o.Status)
+	}
+	o.Status = "APPROVED"
+	o.UpdateTime = s.now().UTC()
+	return nil
+}
+
+// ApproveSubscription approves and activates the subscription as if the subscriber approved it.
+func (s *Server) ApproveSubscription(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return fmt.Errorf("subscription not found: %s", id)
+	}
+	if sub.Status != "APPROVAL_PENDING" {
+		return fmt.Errorf("subscription %s cannot be approved in status %s", id, sub.Status)
+	}
+	s.setSubscriptionStatus(sub, "ACTIVE")
+	return nil
+}
+
+// SetSubscriptionStatus changes the status of the subscription,
+// e.g. to simulate suspensions.
+func (s *Server) SetSubscriptionStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return fmt.Errorf("subscription not found: %s", id)
+	}
+	s.setSubscriptionStatus(sub, status)
+	return nil
+}
+
+func (s *Server) setSubscriptionStatus(sub *fakeSubscription, status string) {
+	now := s.now().UTC()
+	sub.Status = status
+	sub.StatusUpdateTime = now
+	sub.UpdateTime = now
+}
+
+// apiError is a PayPal error response.
+type apiError struct {
+	status int
+
+	Name    string         `json:"name"`
+	Message string         `json:"message"`
+	DebugID string         `json:"debug_id"`
+	Details []*errorDetail `json:"details,omitempty"`
+	Links   []*fakeLink    `json:"links,omitempty"`
+}
+
+type errorDetail struct {
+	Field       string `json:"field,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Issue       string `json:"issue"`
+	Description string `json:"description"`
+}
+
+func newAPIError(status int, name, message, issue, description string) *apiError {
+	e := &apiError{status: status, Name: name, Message: message, DebugID: newID(13)}
+	if issue != "" {
+		e.Details = []*errorDetail{{Issue: issue, Description: description}}
+	}
+	return e
+}
+
+func notFound() *apiError {
+	return newAPIError(http.StatusNotFound, "RESOURCE_NOT_FOUND",
+		"The specified resource does not exist.",
+		"INVALID_RESOURCE_ID", "Specified resource ID does not exist. Please check the resource ID and try again.")
+}
+
+// subscriptionNotFound is [notFound] of the subscriptions, which is described differently.
+func subscriptionNotFound() *apiError {
+	return newAPIError(http.StatusNotFound, "RESOURCE_NOT_FOUND",
+		"The specified resource does not exist.",
+		"INVALID_RESOURCE_ID", "Requested resource ID was not found.")
+}
+
+func unprocessable(issue, description string) *apiError {
+	return newAPIError(http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY",
+		"The requested action could not be performed, semantically incorrect, or failed business validation.",
+		issue, description)
+}
+
+func invalidRequest(field, issue, description string) *apiError {
+	e := newAPIError(http.StatusBadRequest, "INVALID_REQUEST",
+		"Request is not well-formed, syntactically incorrect, or violates schema.",
+		issue, description)
+	e.Details[0].Field = field
+	if field != "" {
+		e.Details[0].Location = "body"
+	}
+	return e
+}
+
+// informationLink returns the link to the document of the error as PayPal does.
+func informationLink(path string, e *apiError) *fakeLink {
+	if strings.HasPrefix(path, "/v1/billing/subscriptions") {
+		return &fakeLink{
+			HRef:   "https://developer.paypal.com/docs/api/v1/billing/subscriptions#" + e.Name,
+			Rel:    "information_link",
+			Method: http.MethodGet,
+		}
+	}
+	anchor := e.Name
+	if len(e.Details) > 0 {
+		anchor = e.Details[0].Issue
+	}
+	return &fakeLink{
+		HRef: "https://developer.paypal.com/docs/api/orders/v2/#error-" + anchor,
+		Rel:  "information_link",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case path == "/v1/oauth2/token":
+		s.serveToken(w, r)
+		return
+	case path == "/checkoutnow" || path == "/webapps/billing/subscriptions":
+		s.serveApprove(w, r)
+		return
+	}
+
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_token",
+			"error_description": "Token signature verification failed",
+		})
+		return
+	}
+
+	s.mu.Lock()
+	res, status, e := s.route(r)
+	s.mu.Unlock()
+	debugID := newID(13)
+	if e != nil {
+		debugID = e.DebugID
+	}
+	w.Header().Set("Paypal-Debug-Id", debugID)
+	switch {
+	case e != nil:
+		e.Links = append(e.Links, informationLink(path, e))
+		writeJSON(w, e.status, e)
+	case res == nil:
+		w.WriteHeader(status)
+	default:
+		writeJSON(w, status, res)
+	}
+}
+
+// route serves the operations of the fake in the catalog shared with the client.
+func (s *Server) route(r *http.Request) (res any, status int, e *apiError) {
+	op, params, _ := operation.Match(r.Method, r.URL.Path)
+	if op == nil {
+		op = &operation.Operation{}
+	}
+	switch op.Name {
+	case "CreateOrder":
+		return s.createOrder(r)
+	case "GetOrder":
+		return s.getOrder(params["order_id"])
+	case "CaptureOrder":
+		return s.captureOrder(r, params["order_id"])
+	case "CreateSubscription":
+		return s.createSubscription(r)
+	case "GetSubscription":
+		return s.getSubscription(params["subscription_id"])
+	case "CancelSubscription":
+		return s.cancelSubscription(params["subscription_id"])
+	case "VerifyWebhookSign":
+		return s.verifyWebhookSign(r)
+	case "SimulateWebhookEvent":
+		return s.simulateWebhookEvent(r)
+	case "GenerateClientToken":
+		return s.generateClientToken()
+	}
+	return nil, 0, newAPIError(http.StatusNotFound, "NOT_FOUND",
+		"The specified resource does not exist.", "", "")
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id == "" || secret == "" ||
+		s.ClientID != "" && (id != s.ClientID || secret != s.ClientSecret) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_client",
+			"error_description": "Client Authentication failed",
+		})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":             "unsupported_grant_type",
+			"error_description": "Grant Type is NULL",
+		})
+		return
+	}
+
+	ttl := s.TokenTTL
+	if ttl <= 0 {
+		ttl = 9 * time.Hour
+	}
+	token := "A21AA" + newID(40)
+	s.mu.Lock()
+	s.tokens[token] = s.now().Add(ttl)
+	s.mu.Unlock()
+	res := map[string]any{
+		"scope":        "https://uri.paypal.com/services/payments/payment",
+		"access_token": token,
+		"token_type":   "Bearer",
+		"app_id":       "APP-80W284485P519543T",
+		"expires_in":   int(ttl.Seconds()),
+		"nonce":        s.now().UTC().Format(time.RFC3339) + newID(16),
+	}
+	if r.PostForm.Get("response_type") == "id_token" {
+		res["id_token"] = "eyJraWQiOi" + newID(40)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) generateClientToken() (res any, status int, e *apiError) {
+	return map[string]any{
+		"client_token": "eyJicmFpbnRyZWUiOn" + newID(40),
+		"expires_in":   3600,
+	}, http.StatusOK, nil
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.tokens[token]
+	return ok && s.now().Before(expiresAt)
+}
+
+// serveApprove approves the order or the subscription of the approve link.
+func (s *Server) serveApprove(w http.ResponseWriter, r *http.Request) {
+	var err error
+	if id := r.URL.Query().Get("token"); id != "" {
+		err = s.ApproveOrder(id)
+	} else {
+		err = s.ApproveSubscription(r.URL.Query().Get("ba_token"))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_, _ = w.Write([]byte("Approved"))
+}
+
+func (s *Server) createOrder(r *http.Request) (res any, status int, e *apiError) {
+	o := new(fakeOrder)
+	if err := json.NewDecoder(r.Body).Decode(o); err != nil {
+		return nil, 0, invalidRequest("", "MALFORMED_REQUEST_JSON", err.Error())
+	}
+	if o.Intent != "CAPTURE" && o.Intent != "AUTHORIZE" {
+		return nil, 0, invalidRequest("/intent", "INVALID_PARAMETER_VALUE",
+			"The value of a field is invalid.")
+	}
+	if len(o.PurchaseUnits) == 0 {
+		return nil, 0, invalidRequest("/purchase_units", "MISSING_REQUIRED_PARAMETER",
+			"A required field / parameter is missing.")
+	}
+	for i, pu := range o.PurchaseUnits {
+		if pu["amount"] == nil {
+			return nil, 0, invalidRequest(fmt.Sprintf("/purchase_units/%d/amount", i),
+				"MISSING_REQUIRED_PARAMETER", "A required field / parameter is missing.")
+		}
+		if pu["reference_id"] == nil {
+			pu["reference_id"] = "default"
+		}
+	}
+
+	o.Status = "CREATED"
+	if o.PaymentSource != nil {
+		if r.Header.Get("PayPal-Request-Id") == "" {
+			return nil, 0, invalidRequest("", "PAYPAL_REQUEST_ID_REQUIRED",
+				"A PayPal-Request-Id is required if you are trying to process payment for an Order.")
+		}
+		card := o.PaymentSource.Card
+		if card == nil || len(card.Number) < 13 || card.Expiry == "" {
+			return nil, 0, invalidRequest("/payment_source/card", "INVALID_PARAMETER_VALUE",
+				"The value of a field is invalid.")
+		}
+		// The card approves the order without a payer
+		o.PaymentSource.Card = &fakeCard{
+			Name:       card.Name,
+			LastDigits: card.Number[len(card.Number)-4:],
+			Brand:      cardBrand(card.Number),
+			Type:       "CREDIT",
+		}
+		o.Status = "APPROVED"
+	}
+
+	now := s.now().UTC()
+	o.ID = newID(17)
+	o.CreateTime, o.UpdateTime = now, now
+	o.Links = []*fakeLink{
+		{HRef: s.URL + "/v2/checkout/orders/" + o.ID, Rel: "self", Method: "GET"},
+		{HRef: s.URL + "/checkoutnow?token=" + o.ID, Rel: "approve", Method: "GET"},
+		{HRef: s.URL + "/v2/checkout/orders/" + o.ID + "/capture", Rel: "capture", Method: "POST"},
+	}
+	s.orders[o.ID] = o
+	if representation(r) {
+		return o, http.StatusCreated, nil
+	}
+	return map[string]any{"id": o.ID, "status": o.Status, "links": o.Links}, http.StatusCreated, nil
+}
+
+// representation reports whether the complete resource is preferred to the minimal one.
+func representation(r *http.Request) bool {
+	return r.Header.Get("Prefer") == "return=representation"
+}
+
+// cardBrand returns the brand of the card by the first digit of the number.
+func cardBrand(number string) string {
+	switch number[0] {
+	case '3':
+		return "AMEX"
+	case '5':
+		return "MASTERCARD"
+	case '6':
+		return "DISCOVER"
+	default:
+		return "VISA"
+	}
+}
+
+func (s *Server) getOrder(id string) (res any, status int, e *apiError) {
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, 0, notFound()
+	}
+	return o, http.StatusOK, nil
+}
+
+func (s *Server) captureOrder(r *http.Request, id string) (res any, status int, e *apiError) {
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, 0, notFound()
+	}
+	switch o.Status {
+	case "APPROVED":
+	case "COMPLETED":
+		return nil, 0, unprocessable("ORDER_ALREADY_CAPTURED", "Order already captured.")
+	default:
+		return nil, 0, unprocessable("ORDER_NOT_APPROVED",
+			"Payer has not yet approved the Order for payment.")
+	}
+	if o.Intent != "CAPTURE" {
+		return nil, 0, unprocessable("ACTION_DOES_NOT_MATCH_INTENT",
+			"Order was created with an intent to 'AUTHORIZE'.")
+	}
+
+	now := s.now().UTC()
+	o.Status = "COMPLETED"
+	o.UpdateTime = now
+	for _, pu := range o.PurchaseUnits {
+		id := newID(17)
+		pu["payments"] = map[string]any{"captures": []map[string]any{{
+			"id":            id,
+			"status":        "COMPLETED",
+			"amount":        pu["amount"],
+			"final_capture": true,
+			"create_time":   now,
+			"update_time":   now,
+			"links": []*fakeLink{
+				{HRef: s.URL + "/v2/payments/captures/" + id, Rel: "self", Method: "GET"},
+			},
+		}}}
+	}
+	if representation(r) {
+		return o, http.StatusCreated, nil
+	}
+
+	// The captures without the order details
+	pus := make([]map[string]any, len(o.PurchaseUnits))
+	for i, pu := range o.PurchaseUnits {
+		pus[i] = map[string]any{"reference_id": pu["reference_id"], "payments": pu["payments"]}
+	}
+	captured := map[string]any{
+		"id":             o.ID,
+		"status":         o.Status,
+		"purchase_units": pus,
+		"links":          []*fakeLink{{HRef: s.URL + "/v2/checkout/orders/" + o.ID, Rel: "self", Method: "GET"}},
+	}
+	if o.PaymentSource != nil {
+		captured["payment_source"] = o.PaymentSource
+	}
+	return captured, http.StatusCreated, nil
+}
+
+func (s *Server) createSubscription(r *http.Request) (res any, status int, e *apiError) {
+	sub := new(fakeSubscription)
+	if err := json.NewDecoder(r.Body).Decode(sub); err != nil {
+		return nil, 0, invalidRequest("", "MALFORMED_REQUEST_JSON", err.Error())
+	}
+	if sub.PlanID == "" {
+		return nil, 0, invalidRequest("/plan_id", "MISSING_REQUIRED_PARAMETER",
+			"A required field / parameter is missing.")
+	}
+
+	now := s.now().UTC()
+	sub.ID = "I-" + newID(12)
+	sub.Status = "APPROVAL_PENDING"
+	sub.StatusUpdateTime, sub.CreateTime, sub.UpdateTime = now, now, now
+	sub.Links = []*fakeLink{
+		{HRef: s.URL + "/webapps/billing/subscriptions?ba_token=" + sub.ID, Rel: "approve", Method: "GET"},
+		{HRef: s.URL + "/v1/billing/subscriptions/" + sub.ID, Rel: "self", Method: "GET"},
+	}
+	s.subscriptions[sub.ID] = sub
+	return sub, http.StatusCreated, nil
+}
+
+func (s *Server) getSubscription(id string) (res any, status int, e *apiError) {
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, 0, subscriptionNotFound()
+	}
+	return sub, http.StatusOK, nil
+}
+
+func (s *Server) cancelSubscription(id string) (res any, status int, e *apiError) {
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, 0, subscriptionNotFound()
+	}
+	if sub.Status != "ACTIVE" && sub.Status != "SUSPENDED" {
+		return nil, 0, unprocessable("SUBSCRIPTION_STATUS_INVALID",
+			"Invalid subscription status for cancel action; subscription status should be active or suspended.")
+	}
+	s.setSubscriptionStatus(sub, "CANCELLED")
+	return nil, http.StatusNoContent, nil
+}
+
+func (s *Server) verifyWebhookSign(r *http.Request) (res any, status int, e *apiError) {
+	req := new(WebhookSignature)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, 0, invalidRequest("", "MALFORMED_REQUEST_JSON", err.Error())
+	}
+	verify := s.VerifyWebhookSign
+	if verify == nil {
+		verify = func(req *WebhookSignature) bool {
+			if s.WebhookID != "" && req.WebhookID != s.WebhookID {
+				return false
+			}
+			if s.Signer != nil {
+				return s.Signer.Verify(req)
+			}
+			return req.TransmissionSig != ""
+		}
+	}
+	vs := "FAILURE"
+	if verify(req) {
+		vs = "SUCCESS"
+	}
+	return map[string]string{"verification_status": vs}, http.StatusOK, nil
+}
+
+// newID returns a random upper-case alphanumeric ID of length n.
+func (s *Server) simulateWebhookEvent(r *http.Request) (res any, status int, e *apiError) {
+	var req struct {
+		WebhookID string `json:"webhook_id"`
+		URL       string `json:"url"`
+		EventType string `json:"event_type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, 0, invalidRequest("", "MALFORMED_REQUEST_JSON", err.Error())
+	}
+	if req.EventType == "" {
+		return nil, 0, invalidRequest("/event_type", "MISSING_REQUIRED_PARAMETER",
+			"A required field / parameter is missing.")
+	}
+	if req.WebhookID == "" && req.URL == "" {
+		return nil, 0, invalidRequest("/url", "MISSING_REQUIRED_PARAMETER",
+			"A required field / parameter is missing.")
+	}
+	return NewWebhookEvent(req.EventType, nil), http.StatusAccepted, nil
+}
+
+func newID(n int) string {
+	bs := make([]byte, (n+1)/2)
+	_, _ = rand.Read(bs)
+	return strings.ToUpper(hex.EncodeToString(bs))[:n]
+}
@@ -0,0 +1,198 @@
+package ptesting_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal"
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func newOrder() *paypal.Order {
+	return &paypal.Order{
+		Intent: paypal.OICapture,
+		PurchaseUnits: []*paypal.PurchaseUnit{
+			{Amount: &paypal.Amount{CurrencyCode: "USD", Value: "12.12"}},
+		},
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	ctx := context.Background()
+	s := ptesting.NewServer()
+	defer s.Close()
+	s.ClientID, s.ClientSecret = "id", "secret"
+
+	token := ptesting.R(paypal.NewClient(s.URL, "id", "secret").Auth(ctx)).NoError(t).V()
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, 32400, token.ExpiresIn)
+	assert.NotZero(t, token.AccessToken)
+
+	c := paypal.NewClient(s.URL, "id", "wrong")
+	ptesting.R(c.Auth(ctx)).EqualError(t, "invalid_client: Client Authentication failed")
+
+	c = paypal.NewClient(s.URL, "id", "secret")
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t)
+	// The client refreshes the rejected token
+	s.ExpireTokens()
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/v2/checkout/orders/1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer expired")
+	res := ptesting.R(http.DefaultClient.Do(req)).NoError(t).V()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestServerOrder(t *testing.T) {
+	ctx := context.Background()
+	s := ptesting.NewServer()
+	defer s.Close()
+	c := paypal.NewClient(s.URL, "id", "secret")
+
+	order := ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: newOrder()})).NoError(t).V()
+	assert.Len(t, order.ID, 17)
+	assert.Equal(t, paypal.OSCreated, order.Status)
+	assert.Empty(t, order.PurchaseUnits, "minimal by default")
+	req := &paypal.CreateOrderReq{Order: newOrder(), Prefer: "return=representation"}
+	ptesting.R(c.CreateOrder(ctx, req)).NoError(t).Do(func(t *testing.T, it *paypal.Order) {
+		assert.Equal(t, "12.12", it.PurchaseUnits[0].Amount.Value)
+		assert.Equal(t, "default", it.PurchaseUnits[0].ReferenceID)
+	})
+
+	var e *paypal.Error
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).ErrorAs(t, &e)
+	assert.Equal(t, 422, e.StatusCode)
+	assert.Equal(t, "UNPROCESSABLE_ENTITY", e.Name)
+	assert.Equal(t, "ORDER_NOT_APPROVED", e.Details[0].Issue)
+	assert.NotZero(t, e.DebugID)
+
+	// Approve by the link as a payer
+	var approve string
+	for _, l := range order.Links {
+		if l.Rel == "approve" {
+			approve = l.HRef
+		}
+	}
+	res := ptesting.R(http.Get(approve)).NoError(t).V()
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	captured := ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).NoError(t).V()
+	assert.Equal(t, paypal.OSCompleted, captured.Status)
+	require.Len(t, captured.Captures(), 1)
+	assert.Equal(t, paypal.CaptureCompleted, captured.Captures()[0].Status)
+	assert.Equal(t, "12.12", captured.Captures()[0].Amount.Value)
+
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).ErrorAs(t, &e)
+	assert.Equal(t, "ORDER_ALREADY_CAPTURED", e.Details[0].Issue)
+	assert.Error(t, s.ApproveOrder(order.ID))
+
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: "NOT-FOUND"})).ErrorAs(t, &e)
+	assert.Equal(t, 404, e.StatusCode)
+	assert.Equal(t, "RESOURCE_NOT_FOUND", e.Name)
+
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: &paypal.Order{
+		Intent: paypal.OICapture,
+	}})).ErrorAs(t, &e)
+	assert.Equal(t, 400, e.StatusCode)
+	assert.Equal(t, "INVALID_REQUEST", e.Name)
+	assert.Equal(t, "/purchase_units", e.Details[0].Field)
+
+	// The card approves the order without a payer
+	withCard := newOrder()
+	withCard.PaymentSource = &paypal.PaymentSource{Card: &paypal.Card{
+		Number:       ptesting.TestCardNumber,
+		Expiry:       ptesting.TestCardExpiry,
+		SecurityCode: ptesting.TestCardSecurityCode,
+	}}
+	ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: withCard})).ErrorAs(t, &e)
+	assert.Equal(t, "PAYPAL_REQUEST_ID_REQUIRED", e.Details[0].Issue)
+	order = ptesting.R(c.CreateOrder(ctx, &paypal.CreateOrderReq{Order: withCard, RequestID: "REQ-1"})).
+		NoError(t).V()
+	assert.Equal(t, paypal.OSApproved, order.Status)
+	ptesting.R(c.CaptureOrder(ctx, &paypal.CaptureOrderReq{ID: order.ID})).NoError(t).Do(
+		func(t *testing.T, it *paypal.Order) {
+			assert.Equal(t, paypal.OSCompleted, it.Status)
+			assert.Equal(t, &paypal.Card{LastDigits: "1111", Brand: "VISA", Type: "CREDIT"}, it.PaymentSource.Card)
+			assert.Equal(t, "12.12", it.Captures()[0].Amount.Value)
+		})
+}
+
+func TestServerSubscription(t *testing.T) {
+	ctx := context.Background()
+	s := ptesting.NewServer()
+	defer s.Close()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time { return now }
+	c := paypal.NewClient(s.URL, "id", "secret")
+
+	sub := ptesting.R(c.CreateSubscription(ctx, &paypal.CreateSubscriptionReq{
+		Subscription: &paypal.Subscription{PlanID: "P-5ML4271244454362WXNWU5NQ"},
+	})).NoError(t).V()
+	assert.Equal(t, paypal.SSApprovalPending, sub.Status)
+	assert.Equal(t, now, sub.CreateTime)
+
+	var e *paypal.Error
+	err := c.CancelSubscription(ctx, &paypal.CancelSubscriptionReq{ID: sub.ID})
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, "SUBSCRIPTION_STATUS_INVALID", e.Details[0].Issue)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, s.ApproveSubscription(sub.ID))
+	sub = ptesting.R(c.GetSubscription(ctx, &paypal.GetSubscriptionReq{ID: sub.ID})).NoError(t).V()
+	assert.Equal(t, paypal.SSActive, sub.Status)
+	assert.Equal(t, now, sub.StatusUpdateTime)
+
+	require.NoError(t, c.CancelSubscription(ctx, &paypal.CancelSubscriptionReq{ID: sub.ID}))
+	ptesting.R(c.GetSubscription(ctx, &paypal.GetSubscriptionReq{ID: sub.ID})).NoError(t).
+		Do(func(t *testing.T, it *paypal.Subscription) {
+			assert.Equal(t, paypal.SSCancelled, it.Status)
+		})
+
+	ptesting.R(c.GetSubscription(ctx, &paypal.GetSubscriptionReq{ID: "I-SF6PBRMK4EPJ"})).ErrorAs(t, &e)
+	assert.Equal(t, 404, e.StatusCode)
+	assert.Equal(t, "INVALID_RESOURCE_ID", e.Details[0].Issue)
+}
+
+func TestServerVerifyWebhookSign(t *testing.T) {
+	ctx := context.Background()
+	s := ptesting.NewServer()
+	defer s.Close()
+	s.WebhookID = "WEBHOOK-ID"
+	c := paypal.NewClient(s.URL, "id", "secret")
+
+	req := &paypal.VerifyWSReq{
+		AuthAlgo:         "SHA256withRSA",
+		TransmissionID:   "103e3700-8b0c-11e6-8695-6b62a8a99ac4",
+		TransmissionSig:  "SIG",
+		TransmissionTime: time.Now(),
+		WebhookID:        "WEBHOOK-ID",
+		WebhookEvent:     json.RawMessage(`{"id":"WH-1"}`),
+	}
+	ptesting.R(c.VerifyWebhookSign(ctx, req)).NoError(t).Equal(true)
+	req.WebhookID = "OTHER"
+	ptesting.R(c.VerifyWebhookSign(ctx, req)).NoError(t).Equal(false)
+
+	s.VerifyWebhookSign = func(req *ptesting.WebhookSignature) bool {
+		return string(req.WebhookEvent) == `{"id":"WH-1"}`
+	}
+	ptesting.R(c.VerifyWebhookSign(ctx, req)).NoError(t).Equal(true)
+
+	// The signatures are verified with the signer
+	s.VerifyWebhookSign = nil
+	s.Signer = ptesting.NewWebhookSigner("WEBHOOK-ID")
+	body := ptesting.R(ptesting.NewWebhookEvent(paypal.PaymentCaptureCompleted, nil).JSON()).NoError(t).V()
+	header := ptesting.R(s.Signer.Sign(body)).NoError(t).V()
+	req = ptesting.R(paypal.NewVerifyWSReq(header, body, "WEBHOOK-ID")).NoError(t).V()
+	ptesting.R(c.VerifyWebhookSign(ctx, req)).NoError(t).Equal(true)
+	req.WebhookEvent = json.RawMessage(`{"id":"WH-FORGED"}`)
+	ptesting.R(c.VerifyWebhookSign(ctx, req)).NoError(t).Equal(false)
+}
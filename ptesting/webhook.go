@@ -0,0 +1,275 @@
+package ptesting
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobaai/paypal/internal/webhook"
+)
+
+// TestCertURL is the cert URL in the webhooks signed by [WebhookSigner].
+const TestCertURL = "https://api.sandbox.paypal.com/v1/notifications/certs/CERT-360caa42-fca2a594-TEST"
+
+// WebhookEvent is a webhook event in the shape of the PayPal webhooks,
+// which is decoded as paypal.Webhook.
+type WebhookEvent struct {
+	ID              string      `json:"id"`
+	CreateTime      time.Time   `json:"create_time"`
+	ResourceType    string      `json:"resource_type"`
+	ResourceVersion string      `json:"resource_version,omitempty"`
+	EventType       string      `json:"event_type"`
+	EventVersion    string      `json:"event_version"`
+	Summary         string      `json:"summary"`
+	Resource        any         `json:"resource"`
+	Links           []EventLink `json:"links,omitempty"`
+}
+
+// EventLink is a link of [WebhookEvent].
+type EventLink struct {
+	HRef   string `json:"href"`
+	Rel    string `json:"rel"`
+	Method string `json:"method"`
+}
+
+// NewWebhookEvent returns a new event of the type, e.g. paypal.PaymentCaptureCompleted,
+// with the resource type and version derived from the event type.
+//
+// The resource is usually a typed one, e.g. *paypal.Capture for "PAYMENT.CAPTURE.*".
+// If it is nil, a minimal resource with a random ID is used, which is decoded by
+// paypal.Webhook.Decode into the typed one of the event type, e.g. a *paypal.Capture
+// with the status "DECLINED" and an amount for "PAYMENT.CAPTURE.DENIED".
+func NewWebhookEvent[E ~string](et E, resource any) *WebhookEvent {
+	id := "WH-" + newID(17) + "-" + newID(17)
+	res := &WebhookEvent{
+		ID:           id,
+		CreateTime:   time.Now().UTC().Truncate(time.Second),
+		EventType:    string(et),
+		EventVersion: "1.0",
+		Summary:      "Test " + strings.ToLower(string(et)) + " event",
+		Resource:     resource,
+		Links: []EventLink{
+			{
+				HRef:   "https://api.sandbox.paypal.com/v1/notifications/webhooks-events/" + id,
+				Rel:    "self",
+				Method: http.MethodGet,
+			},
+			{
+				HRef:   "https://api.sandbox.paypal.com/v1/notifications/webhooks-events/" + id + "/resend",
+				Rel:    "resend",
+				Method: http.MethodPost,
+			},
+		},
+	}
+	res.ResourceType = webhook.ResourceType(res.EventType)
+	if r, ok := webhook.LookupResource(res.EventType); ok {
+		res.ResourceVersion = r.Version
+	}
+	if resource == nil {
+		res.Resource = webhook.SampleResource(res.EventType, newID(17))
+	}
+	return res
+}
+
+// JSON returns the event in JSON format.
+func (ev *WebhookEvent) JSON() ([]byte, error) {
+	return json.Marshal(ev)
+}
+
+// testPKI is the test CA and the leaf certificate shared by all signers,
+// as generating RSA keys is slow.
+var testPKI struct {
+	once    sync.Once
+	roots   *x509.CertPool
+	key     *rsa.PrivateKey
+	certPEM []byte
+	err     error
+}
+
+func loadTestPKI() error {
+	testPKI.once.Do(func() {
+		now := time.Now()
+		caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			testPKI.err = fmt.Errorf("generate CA key: %w", err)
+			return
+		}
+		caTmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "ptesting Root CA"},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+		if err != nil {
+			testPKI.err = fmt.Errorf("create CA cert: %w", err)
+			return
+		}
+		ca, err := x509.ParseCertificate(caDER)
+		if err != nil {
+			testPKI.err = fmt.Errorf("parse CA cert: %w", err)
+			return
+		}
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			testPKI.err = fmt.Errorf("generate key: %w", err)
+			return
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: "messageverificationcerts.sandbox.paypal.com"},
+			DNSNames:     []string{"messageverificationcerts.sandbox.paypal.com"},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(365 * 24 * time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+		}, ca, &key.PublicKey, caKey)
+		if err != nil {
+			testPKI.err = fmt.Errorf("create cert: %w", err)
+			return
+		}
+
+		testPKI.roots = x509.NewCertPool()
+		testPKI.roots.AddCert(ca)
+		testPKI.key = key
+		testPKI.certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	})
+	return testPKI.err
+}
+
+// WebhookSigner signs webhooks with a test certificate issued by a test CA,
+// so that they are accepted by a paypal.CertVerifier configured with the signer:
+//
+//	s := ptesting.NewWebhookSigner("WEBHOOK-ID")
+//	v := &paypal.CertVerifier{HTTPClient: s.HTTPClient(), Roots: s.Roots()}
+//	h := paypal.NewWebhookHandler(v, "WEBHOOK-ID")
+//	req := s.Request("/webhook", ptesting.NewWebhookEvent(paypal.PaymentCaptureCompleted, capture))
+//
+// The certificate is served at [TestCertURL] by the round tripper of [WebhookSigner.HTTPClient].
+type WebhookSigner struct {
+	// WebhookID is the webhook ID in the signed strings.
+	WebhookID string
+	// Now returns the transmission time, [time.Now] is used if nil.
+	Now func() time.Time
+}
+
+// NewWebhookSigner returns a new [WebhookSigner] signing webhooks for the webhook ID.
+// It panics if the test certificates could not be generated.
+func NewWebhookSigner(webhookID string) *WebhookSigner {
+	if err := loadTestPKI(); err != nil {
+		panic(err)
+	}
+	return &WebhookSigner{WebhookID: webhookID}
+}
+
+// Roots returns the pool with the test CA.
+func (s *WebhookSigner) Roots() *x509.CertPool {
+	return testPKI.roots
+}
+
+// CertPEM returns the test certificate in PEM format.
+func (s *WebhookSigner) CertPEM() []byte {
+	return testPKI.certPEM
+}
+
+// HTTPClient returns a client serving the test certificate at [TestCertURL]
+// without network access, and 404 for other URLs.
+func (s *WebhookSigner) HTTPClient() *http.Client {
+	return &http.Client{Transport: s}
+}
+
+func (s *WebhookSigner) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if req.Method != http.MethodGet || req.URL.String() != TestCertURL {
+		return newResponse(req, http.StatusNotFound, "text/plain", "404 page not found\n"), nil
+	}
+	return newResponse(req, http.StatusOK, "application/x-pem-file", string(testPKI.certPEM)), nil
+}
+
+// Sign returns the headers of the webhook request with the raw body.
+func (s *WebhookSigner) Sign(body []byte) (http.Header, error) {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	tid := newID(8) + "-" + newID(4) + "-" + newID(4)
+	tt := now().UTC().Truncate(time.Second).Format(time.RFC3339)
+	sig, err := s.SignString(webhook.SignedString(tid, tt, s.WebhookID, body))
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(webhook.HeaderAuthAlgo, "SHA256withRSA")
+	header.Set(webhook.HeaderCertURL, TestCertURL)
+	header.Set(webhook.HeaderTransmissionID, tid)
+	header.Set(webhook.HeaderTransmissionSig, sig)
+	header.Set(webhook.HeaderTransmissionTime, tt)
+	return header, nil
+}
+
+// SignString returns the base64 signature of the signed string with the test certificate,
+// e.g. to test the verification of a signed string written by hand.
+func (s *WebhookSigner) SignString(signed string) (string, error) {
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, testPKI.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("sign: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Verify reports whether the signature is signed by the signer, i.e. with the test certificate,
+// for the webhook ID of the request.
+// The webhook event must be in the bytes signed, e.g. a compact one as [WebhookEvent.JSON].
+func (s *WebhookSigner) Verify(req *WebhookSignature) bool {
+	if req.AuthAlgo != "SHA256withRSA" || req.CertURL != TestCertURL {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(req.TransmissionSig)
+	if err != nil {
+		return false
+	}
+	signed := webhook.SignedString(req.TransmissionID, req.TransmissionTime, req.WebhookID, req.WebhookEvent)
+	hashed := sha256.Sum256([]byte(signed))
+	return rsa.VerifyPKCS1v15(&testPKI.key.PublicKey, crypto.SHA256, hashed[:], sig) == nil
+}
+
+// Request returns a new signed webhook request of the event to the target,
+// which is a URL or a path for http.Handler tests.
+// It panics on errors as [net/http/httptest.NewRequest] does.
+func (s *WebhookSigner) Request(target string, ev *WebhookEvent) *http.Request {
+	body, err := ev.JSON()
+	if err != nil {
+		panic(fmt.Sprintf("marshal event: %v", err))
+	}
+	header, err := s.Sign(body)
+	if err != nil {
+		panic(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		panic(fmt.Sprintf("new request: %v", err))
+	}
+	req.Header = header
+	return req
+}
@@ -0,0 +1,111 @@
+package ptesting_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal"
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestNewWebhookEvent(t *testing.T) {
+	ev := ptesting.NewWebhookEvent(paypal.BillingSubscriptionActivated, &paypal.Subscription{
+		ID:     "I-BW452GLLEP1G",
+		Status: paypal.SSActive,
+	})
+	body := ptesting.R(ev.JSON()).NoError(t).V()
+	wh := new(paypal.Webhook)
+	require.NoError(t, json.Unmarshal(body, wh))
+	assert.Equal(t, ev.ID, wh.ID)
+	assert.Equal(t, paypal.BillingSubscriptionActivated, wh.EventType)
+	assert.Equal(t, paypal.RTSubscription, wh.ResourceType)
+	assert.Equal(t, "2.0", wh.ResourceVersion)
+	assert.Equal(t, "I-BW452GLLEP1G", wh.ResourceID())
+	ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+		require.IsType(t, &paypal.Subscription{}, it)
+		assert.Equal(t, paypal.SSActive, it.(*paypal.Subscription).Status)
+	})
+
+	// A minimal typed resource is generated for the known event types
+	for _, et := range []paypal.EventType{
+		paypal.PaymentCaptureDenied, paypal.PaymentSaleCompleted, paypal.BillingSubscriptionActivated,
+	} {
+		body = ptesting.R(ptesting.NewWebhookEvent(et, nil).JSON()).NoError(t).V()
+		wh = new(paypal.Webhook)
+		require.NoError(t, json.Unmarshal(body, wh))
+		assert.Len(t, wh.ResourceID(), 17, et)
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			switch it := it.(type) {
+			case *paypal.Capture:
+				assert.Equal(t, paypal.CaptureDeclined, it.Status)
+				assert.Equal(t, "10.00", it.Amount.Value)
+			case *paypal.Sale:
+				assert.Equal(t, paypal.SaleCompleted, it.State)
+				assert.Equal(t, "10.00", it.Amount.Total)
+			case *paypal.Subscription:
+				assert.Equal(t, paypal.SSActive, it.Status)
+			default:
+				t.Errorf("unexpected resource of %s: %T", et, it)
+			}
+		})
+	}
+
+	ev = ptesting.NewWebhookEvent("VAULT.PAYMENT-TOKEN.CREATED", nil)
+	assert.Equal(t, "payment-token", ev.ResourceType)
+	assert.Len(t, ev.Resource.(map[string]any)["id"], 17)
+}
+
+func TestWebhookSigner(t *testing.T) {
+	s := ptesting.NewWebhookSigner("WEBHOOK-ID")
+	v := &paypal.CertVerifier{HTTPClient: s.HTTPClient(), Roots: s.Roots()}
+	h := paypal.NewWebhookHandler(v, "WEBHOOK-ID")
+	var got *paypal.Capture
+	h.Handle(paypal.PaymentCaptureCompleted, func(ctx context.Context, wh *paypal.Webhook) (err error) {
+		got, err = paypal.ResourceAs[paypal.Capture](wh)
+		return
+	})
+	serve := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+	ev := ptesting.NewWebhookEvent(paypal.PaymentCaptureCompleted, &paypal.Capture{
+		ID:     "42311647XV020574X",
+		Status: paypal.CaptureCompleted,
+		Amount: &paypal.Amount{CurrencyCode: "USD", Value: "0.48"},
+	})
+
+	assert.Equal(t, http.StatusOK, serve(s.Request("/webhook", ev)))
+	require.NotNil(t, got)
+	assert.Equal(t, "42311647XV020574X", got.ID)
+	assert.Equal(t, "0.48", got.Amount.Value)
+
+	// Tampered
+	r := s.Request("/webhook", ev)
+	body := ptesting.R(io.ReadAll(r.Body)).NoError(t).V()
+	r.Body = io.NopCloser(bytes.NewReader(bytes.Replace(body, []byte("0.48"), []byte("4.80"), 1)))
+	assert.Equal(t, http.StatusUnauthorized, serve(r))
+
+	// Another webhook
+	other := ptesting.NewWebhookSigner("OTHER")
+	assert.Equal(t, http.StatusUnauthorized, serve(other.Request("/webhook", ev)))
+
+	// Stale
+	other.WebhookID = "WEBHOOK-ID"
+	other.Now = func() time.Time { return time.Now().Add(-time.Hour) }
+	h.Guard = paypal.NewReplayGuard(nil)
+	assert.Equal(t, http.StatusBadRequest, serve(other.Request("/webhook", ev)))
+
+	// Not verified by the system roots
+	h.Verifier = &paypal.CertVerifier{HTTPClient: s.HTTPClient()}
+	assert.Equal(t, http.StatusUnauthorized, serve(s.Request("/webhook", ev)))
+}
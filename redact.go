@@ -0,0 +1,160 @@
+package paypal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RedactedValue replaces the redacted values in the logs.
+const RedactedValue = "REDACTED"
+
+// DefaultRedactPaths are the JSON paths of the personal data and the secrets
+// in the PayPal requests and responses, see [RedactPolicy] for the syntax.
+var DefaultRedactPaths = []string{
+	// Secrets
+	"$..access_token",
+	"$..refresh_token",
+	"$..id_token",
+	"$..client_token",
+	"$..client_secret",
+	"$..code",
+	"$..code_verifier",
+	"$..nonce",
+	"$..password",
+	// Payer
+	"$..email_address",
+	"$..email",
+	"$..phone",
+	"$..phone_number",
+	"$..phone_numbers",
+	"$..birth_date",
+	"$..tax_info",
+	"$..given_name",
+	"$..surname",
+	"$..full_name",
+	"$..payer.name",
+	"$..shipping.name",
+	"$..address",
+	"$..addresses",
+	// Cards and banks
+	"$..card.number",
+	"$..card.security_code",
+	"$..card.expiry",
+	"$..card.name",
+	"$..card.billing_address",
+	"$..bank.account_number",
+	"$..iban",
+	"$..bic",
+}
+
+// RedactPolicy replaces the values at the JSON paths with [RedactedValue].
+//
+// A path starts with "$", followed by the segments:
+//
+//   - ".name" matches the field of an object, ".*" matches all fields.
+//   - "..name" matches the field of an object at any depth.
+//   - "[*]" matches all elements of an array.
+//
+// For example, "$.purchase_units[*].shipping" and "$..email_address".
+type RedactPolicy struct {
+	paths [][]redactSegment
+}
+
+type redactSegment struct {
+	key       string // "*" for all fields
+	recursive bool
+	elements  bool
+}
+
+// NewRedactPolicy returns a new [RedactPolicy] of the paths,
+// or an error if a path is invalid.
+func NewRedactPolicy(paths ...string) (res *RedactPolicy, err error) {
+	res = &RedactPolicy{}
+	for _, p := range paths {
+		segs, err := parseRedactPath(p)
+		if err != nil {
+			return nil, err
+		}
+		res.paths = append(res.paths, segs)
+	}
+	return
+}
+
+// MustRedactPolicy is like [NewRedactPolicy] but panics if a path is invalid.
+func MustRedactPolicy(paths ...string) *RedactPolicy {
+	p, err := NewRedactPolicy(paths...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func parseRedactPath(p string) (res []redactSegment, err error) {
+	if !strings.HasPrefix(p, "$") || len(p) == 1 {
+		return nil, fmt.Errorf("invalid redact path %q: must start with $ and a segment", p)
+	}
+	s := p[1:]
+	for s != "" {
+		var seg redactSegment
+		switch {
+		case strings.HasPrefix(s, "[*]"):
+			seg.elements = true
+			s = s[3:]
+		case strings.HasPrefix(s, ".."):
+			seg.recursive = true
+			s = s[2:]
+		case strings.HasPrefix(s, "."):
+			s = s[1:]
+		default:
+			return nil, fmt.Errorf("invalid redact path %q at %q", p, s)
+		}
+		if !seg.elements {
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			if seg.key = s[:end]; seg.key == "" {
+				return nil, fmt.Errorf("invalid redact path %q: empty field name", p)
+			}
+			s = s[end:]
+		}
+		res = append(res, seg)
+	}
+	return
+}
+
+// Redact returns the JSON value, as unmarshaled into any, with the values at the paths redacted.
+// The maps and the slices of the value are modified in place.
+func (p *RedactPolicy) Redact(v any) any {
+	for _, segs := range p.paths {
+		v = redactAt(v, segs)
+	}
+	return v
+}
+
+func redactAt(v any, segs []redactSegment) any {
+	if len(segs) == 0 {
+		return RedactedValue
+	}
+	seg, rest := segs[0], segs[1:]
+	switch v := v.(type) {
+	case map[string]any:
+		for k, vv := range v {
+			if !seg.elements && (seg.key == "*" || seg.key == k) {
+				vv = redactAt(vv, rest)
+			} else if seg.recursive {
+				vv = redactAt(vv, segs)
+			}
+			v[k] = vv
+		}
+	case []any:
+		for i, vv := range v {
+			if seg.elements {
+				v[i] = redactAt(vv, rest)
+			} else if seg.recursive {
+				v[i] = redactAt(vv, segs)
+			}
+		}
+	}
+	return v
+}
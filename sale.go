@@ -1,11 +1,29 @@
 package paypal
 
+import "time"
+
+type SaleState string
+
+const (
+	SaleCompleted         SaleState = "completed"
+	SalePartiallyRefunded SaleState = "partially_refunded"
+	SalePending           SaleState = "pending"
+	SaleRefunded          SaleState = "refunded"
+	SaleDenied            SaleState = "denied"
+)
+
 type Sale struct {
-	ID                 string `json:"id,omitempty"`
-	BillingAgreementId string `json:"billing_agreement_id,omitempty"` // Subscription ID
-	Amount             struct {
-		Total    string `json:"total,omitempty"`
-		Currency string `json:"currency,omitempty"`
-	} `json:"amount,omitempty"`
-	Links []*Link `json:"links,omitempty"`
+	ID                 string     `json:"id,omitempty"`
+	State              SaleState  `json:"state,omitempty"`
+	BillingAgreementId string     `json:"billing_agreement_id,omitempty"` // Subscription ID
+	Amount             SaleAmount `json:"amount,omitempty"`
+	CreateTime         time.Time  `json:"create_time,omitempty"`
+	UpdateTime         time.Time  `json:"update_time,omitempty"`
+	Links              []*Link    `json:"links,omitempty"`
+}
+
+// SaleAmount is the amount of a sale, which is in the shape of the Payments v1 API.
+type SaleAmount struct {
+	Total    string `json:"total,omitempty"`
+	Currency string `json:"currency,omitempty"`
 }
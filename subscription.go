@@ -2,7 +2,9 @@ package paypal
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 )
 
 type PaymentPreferences struct {
@@ -24,24 +26,97 @@ const (
 	SSExpired         SubscriptionStatus = "EXPIRED"
 )
 
+// ValidTransition reports whether the status can be changed to the next one.
+// The cancelled and expired subscriptions cannot be changed anymore.
+func (s SubscriptionStatus) ValidTransition(next SubscriptionStatus) bool {
+	switch s {
+	case SSApprovalPending:
+		return next != SSSuspended
+	case SSApproved:
+		return next == SSActive || next == SSCancelled || next == SSExpired
+	case SSActive:
+		return next == SSSuspended || next == SSCancelled || next == SSExpired
+	case SSSuspended:
+		return next == SSActive || next == SSCancelled || next == SSExpired
+	}
+	return false
+}
+
 type Subscription struct {
-	ID       string             `json:"id,omitempty"`
-	PlanID   string             `json:"plan_id,omitempty"`
-	Quantity string             `json:"quantity,omitempty"`
-	Status   SubscriptionStatus `json:"status,omitempty"`
-	Plan     *SubscriptionPlan  `json:"plan,omitempty"`
+	ID               string             `json:"id,omitempty"`
+	PlanID           string             `json:"plan_id,omitempty"`
+	Quantity         string             `json:"quantity,omitempty"`
+	Status           SubscriptionStatus `json:"status,omitempty"`
+	StatusUpdateTime time.Time          `json:"status_update_time,omitempty"`
+	Plan             *SubscriptionPlan  `json:"plan,omitempty"`
+	BillingInfo      *BillingInfo       `json:"billing_info,omitempty"`
+	CreateTime       time.Time          `json:"create_time,omitempty"`
+	UpdateTime       time.Time          `json:"update_time,omitempty"`
+	Links            []*Link            `json:"links,omitempty"`
+}
+
+// LastModified returns the later one of the update time and the status update time.
+func (s *Subscription) LastModified() time.Time {
+	if s.StatusUpdateTime.After(s.UpdateTime) {
+		return s.StatusUpdateTime
+	}
+	return s.UpdateTime
+}
+
+// BillingInfo is the billing details of a subscription.
+type BillingInfo struct {
+	OutstandingBalance  *Amount                  `json:"outstanding_balance,omitempty"`
+	LastPayment         *LastPayment             `json:"last_payment,omitempty"`
+	LastFailedPayment   *FailedPayment           `json:"last_failed_payment,omitempty"`
+	NextBillingTime     time.Time                `json:"next_billing_time,omitempty"`
+	FailedPaymentsCount int                      `json:"failed_payments_count,omitempty"`
+	CycleExecutions     []*BillingCycleExecution `json:"cycle_executions,omitempty"`
+}
+
+type LastPayment struct {
+	Amount *Amount   `json:"amount,omitempty"`
+	Time   time.Time `json:"time,omitempty"`
+}
+
+type FailedPayment struct {
+	Amount     *Amount   `json:"amount,omitempty"`
+	Time       time.Time `json:"time,omitempty"`
+	ReasonCode string    `json:"reason_code,omitempty"`
+}
+
+type BillingCycleExecution struct {
+	TenureType      string `json:"tenure_type,omitempty"` // REGULAR or TRIAL
+	Sequence        int    `json:"sequence,omitempty"`
+	CyclesCompleted int    `json:"cycles_completed,omitempty"`
+	CyclesRemaining int    `json:"cycles_remaining,omitempty"`
+	TotalCycles     int    `json:"total_cycles,omitempty"`
 }
 
 type CreateSubscriptionReq struct {
 	*Subscription
 }
 
+// validate validates the amounts before sending the request.
+func (r *CreateSubscriptionReq) validate() error {
+	if r.Subscription == nil || r.Plan == nil || r.Plan.PaymentPreferences == nil ||
+		r.Plan.PaymentPreferences.SetupFee == nil {
+		return nil
+	}
+	if err := r.Plan.PaymentPreferences.SetupFee.Validate(); err != nil {
+		return fmt.Errorf("plan.payment_preferences.setup_fee: %w", err)
+	}
+	return nil
+}
+
 // CreateSubscription creates a subscription.
 //
 // See https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_create
 func (c *Client) CreateSubscription(ctx context.Context, req *CreateSubscriptionReq,
 ) (res *Subscription, err error) {
 	ctx = WithOperation(ctx, "CreateSubscription")
+	if err = req.validate(); err != nil {
+		return
+	}
 	return JSON[Subscription](ctx, c, http.MethodPost, "/v1/billing/subscriptions", req)
 }
 
@@ -0,0 +1,273 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SubscriptionEventKind is the kind of the domain events emitted by [SubscriptionProjector].
+type SubscriptionEventKind string
+
+const (
+	SEKCreated       SubscriptionEventKind = "CREATED"
+	SEKApproved      SubscriptionEventKind = "APPROVED"
+	SEKActivated     SubscriptionEventKind = "ACTIVATED"
+	SEKReactivated   SubscriptionEventKind = "REACTIVATED"
+	SEKSuspended     SubscriptionEventKind = "SUSPENDED"
+	SEKCancelled     SubscriptionEventKind = "CANCELLED"
+	SEKExpired       SubscriptionEventKind = "EXPIRED"
+	SEKUpdated       SubscriptionEventKind = "UPDATED"
+	SEKPaymentFailed SubscriptionEventKind = "PAYMENT_FAILED"
+	SEKRenewed       SubscriptionEventKind = "RENEWED" // A recurring payment completed
+)
+
+// SubscriptionEvent is a domain event of a subscription.
+type SubscriptionEvent struct {
+	Kind SubscriptionEventKind
+	// Subscription is the projected subscription after the event.
+	Subscription *Subscription
+	// Previous is the projected subscription before the event, nil if it was unknown.
+	Previous *Subscription
+	// Sale is the payment of the [SEKRenewed] and [SEKPaymentFailed] events of sales.
+	Sale    *Sale
+	Webhook *Webhook
+}
+
+// SubscriptionStore stores the projected subscriptions.
+type SubscriptionStore interface {
+	// LoadSubscription returns nil without error if the subscription is not found.
+	LoadSubscription(ctx context.Context, id string) (*Subscription, error)
+	SaveSubscription(ctx context.Context, s *Subscription) error
+}
+
+// MemorySubscriptionStore is an in-memory [SubscriptionStore].
+type MemorySubscriptionStore struct {
+	mu sync.RWMutex
+	m  map[string]*Subscription
+}
+
+func NewMemorySubscriptionStore() *MemorySubscriptionStore {
+	return &MemorySubscriptionStore{m: map[string]*Subscription{}}
+}
+
+func (s *MemorySubscriptionStore) LoadSubscription(_ context.Context, id string,
+) (*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m[id], nil
+}
+
+func (s *MemorySubscriptionStore) SaveSubscription(_ context.Context, sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[sub.ID] = sub
+	return nil
+}
+
+// SubscriptionGetter gets the subscriptions from PayPal, which is implemented by [Client].
+type SubscriptionGetter interface {
+	GetSubscription(ctx context.Context, req *GetSubscriptionReq) (*Subscription, error)
+}
+
+// SubscriptionProjector maintains the subscriptions in the store
+// with the "BILLING.SUBSCRIPTION.*" and "PAYMENT.SALE.*" webhooks,
+// which may be delivered out of order or more than once.
+//
+// A subscription webhook is applied only if it is newer than the projected one
+// by [Subscription.LastModified], and the status change is valid by
+// [SubscriptionStatus.ValidTransition]. Otherwise, the stale ones are ignored,
+// and the conflicts are resolved by getting the subscription from PayPal.
+//
+// The sale webhooks with a billing agreement ID are the payments of subscriptions,
+// the unknown subscriptions are got from PayPal.
+type SubscriptionProjector struct {
+	Store  SubscriptionStore
+	Getter SubscriptionGetter
+	// OnEvent is called with the domain events before the subscription is saved,
+	// the webhook is redelivered if it returns an error,
+	// so the events may be emitted more than once.
+	OnEvent func(ctx context.Context, e *SubscriptionEvent) error
+
+	mu    sync.Mutex
+	locks map[string]*subscriptionLock
+}
+
+type subscriptionLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewSubscriptionProjector returns a new [SubscriptionProjector],
+// the getter is usually a [Client].
+func NewSubscriptionProjector(getter SubscriptionGetter, store SubscriptionStore,
+) *SubscriptionProjector {
+	return &SubscriptionProjector{Store: store, Getter: getter}
+}
+
+// Register registers the projector to the router for the webhooks it consumes.
+func (p *SubscriptionProjector) Register(r *WebhookRouter) {
+	r.Handle("BILLING.SUBSCRIPTION.*", p.Handle)
+	r.Handle("PAYMENT.SALE.*", p.Handle)
+}
+
+// Handle handles the webhook, it is a [WebhookFunc].
+// The webhooks of the same subscription are handled one at a time.
+func (p *SubscriptionProjector) Handle(ctx context.Context, wh *Webhook) (err error) {
+	switch wh.ResourceType {
+	case RTSubscription:
+		return p.handleSubscription(ctx, wh)
+	case RTSale:
+		return p.handleSale(ctx, wh)
+	}
+	return nil
+}
+
+func (p *SubscriptionProjector) handleSubscription(ctx context.Context, wh *Webhook) (err error) {
+	in, err := ResourceAs[Subscription](wh)
+	if err != nil {
+		return
+	}
+	defer p.lock(in.ID)()
+	prev, err := p.Store.LoadSubscription(ctx, in.ID)
+	if err != nil {
+		return fmt.Errorf("load subscription: %w", err)
+	}
+
+	next := in
+	if prev != nil {
+		if !in.LastModified().After(prev.LastModified()) {
+			// Stale or duplicate
+			return nil
+		}
+		if prev.Status != in.Status && !prev.Status.ValidTransition(in.Status) {
+			if next, err = p.get(ctx, in.ID); err != nil {
+				return
+			}
+			if !next.LastModified().After(prev.LastModified()) {
+				return nil
+			}
+		}
+	}
+
+	for _, kind := range subscriptionEventKinds(prev, next, wh.EventType) {
+		e := &SubscriptionEvent{Kind: kind, Subscription: next, Previous: prev, Webhook: wh}
+		if err = p.emit(ctx, e); err != nil {
+			return
+		}
+	}
+	if err = p.Store.SaveSubscription(ctx, next); err != nil {
+		return fmt.Errorf("save subscription: %w", err)
+	}
+	return
+}
+
+// subscriptionEventKinds returns the kinds of the change from prev to next.
+func subscriptionEventKinds(prev, next *Subscription, et EventType) (res []SubscriptionEventKind) {
+	if prev == nil {
+		if et == BillingSubscriptionCreated || next.Status == SSApprovalPending {
+			res = append(res, SEKCreated)
+		}
+	}
+	if prev == nil || prev.Status != next.Status {
+		switch next.Status {
+		case SSApproved:
+			res = append(res, SEKApproved)
+		case SSActive:
+			if prev != nil && prev.Status == SSSuspended {
+				res = append(res, SEKReactivated)
+			} else {
+				res = append(res, SEKActivated)
+			}
+		case SSSuspended:
+			res = append(res, SEKSuspended)
+		case SSCancelled:
+			res = append(res, SEKCancelled)
+		case SSExpired:
+			res = append(res, SEKExpired)
+		}
+	}
+	switch {
+	case et == BillingSubscriptionPaymentFailed:
+		res = append(res, SEKPaymentFailed)
+	case len(res) == 0 && prev != nil:
+		res = append(res, SEKUpdated)
+	}
+	return
+}
+
+func (p *SubscriptionProjector) handleSale(ctx context.Context, wh *Webhook) (err error) {
+	var kind SubscriptionEventKind
+	switch wh.EventType {
+	case PaymentSaleCompleted:
+		kind = SEKRenewed
+	case PaymentSaleDenied:
+		kind = SEKPaymentFailed
+	default:
+		return nil
+	}
+	sale, err := ResourceAs[Sale](wh)
+	if err != nil || sale.BillingAgreementId == "" {
+		return
+	}
+	defer p.lock(sale.BillingAgreementId)()
+
+	sub, err := p.Store.LoadSubscription(ctx, sale.BillingAgreementId)
+	if err != nil {
+		return fmt.Errorf("load subscription: %w", err)
+	}
+	if sub == nil {
+		if sub, err = p.get(ctx, sale.BillingAgreementId); err != nil {
+			return
+		}
+		if err = p.Store.SaveSubscription(ctx, sub); err != nil {
+			return fmt.Errorf("save subscription: %w", err)
+		}
+	}
+	return p.emit(ctx, &SubscriptionEvent{
+		Kind: kind, Subscription: sub, Previous: sub, Sale: sale, Webhook: wh,
+	})
+}
+
+// lock locks the subscription of the ID to serialize its loads and saves,
+// and returns the function to unlock it.
+func (p *SubscriptionProjector) lock(id string) (unlock func()) {
+	p.mu.Lock()
+	if p.locks == nil {
+		p.locks = map[string]*subscriptionLock{}
+	}
+	l := p.locks[id]
+	if l == nil {
+		l = &subscriptionLock{}
+		p.locks[id] = l
+	}
+	l.refs++
+	p.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		p.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(p.locks, id)
+		}
+		p.mu.Unlock()
+	}
+}
+
+func (p *SubscriptionProjector) get(ctx context.Context, id string) (res *Subscription, err error) {
+	if res, err = p.Getter.GetSubscription(ctx, &GetSubscriptionReq{ID: id}); err != nil {
+		return nil, fmt.Errorf("get subscription: %w", err)
+	}
+	return
+}
+
+func (p *SubscriptionProjector) emit(ctx context.Context, e *SubscriptionEvent) error {
+	if p.OnEvent == nil {
+		return nil
+	}
+	if err := p.OnEvent(ctx, e); err != nil {
+		return fmt.Errorf("on %s event: %w", e.Kind, err)
+	}
+	return nil
+}
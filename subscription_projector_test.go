@@ -0,0 +1,185 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestSubscriptionStatusValidTransition(t *testing.T) {
+	assert.True(t, SSApprovalPending.ValidTransition(SSActive))
+	assert.True(t, SSActive.ValidTransition(SSSuspended))
+	assert.True(t, SSSuspended.ValidTransition(SSActive))
+	assert.False(t, SSApprovalPending.ValidTransition(SSSuspended))
+	assert.False(t, SSApproved.ValidTransition(SSApprovalPending))
+	assert.False(t, SSCancelled.ValidTransition(SSActive))
+	assert.False(t, SSExpired.ValidTransition(SSActive))
+}
+
+type subscriptionGetterFunc func(ctx context.Context, req *GetSubscriptionReq) (*Subscription, error)
+
+func (f subscriptionGetterFunc) GetSubscription(ctx context.Context, req *GetSubscriptionReq,
+) (*Subscription, error) {
+	return f(ctx, req)
+}
+
+func newSubscriptionWebhook(t *testing.T, et EventType, status SubscriptionStatus, minute int,
+) *Webhook {
+	s := &Subscription{
+		ID:               "I-BW452GLLEP1G",
+		Status:           status,
+		StatusUpdateTime: time.Date(2023, 1, 1, 0, minute, 0, 0, time.UTC),
+		UpdateTime:       time.Date(2023, 1, 1, 0, minute, 0, 0, time.UTC),
+	}
+	return newResourceWebhook(t, et, RTSubscription, s)
+}
+
+func newResourceWebhook(t *testing.T, et EventType, rt string, resource any) *Webhook {
+	bs, err := json.Marshal(map[string]any{
+		"id":            fmt.Sprintf("WH-%s-%p", et, resource),
+		"event_type":    et,
+		"resource_type": rt,
+		"resource":      resource,
+	})
+	require.NoError(t, err)
+	wh := new(Webhook)
+	require.NoError(t, json.Unmarshal(bs, wh))
+	return wh
+}
+
+func TestSubscriptionProjector(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySubscriptionStore()
+	var gets int
+	remote := &Subscription{
+		ID:               "I-BW452GLLEP1G",
+		Status:           SSSuspended,
+		StatusUpdateTime: time.Date(2023, 1, 1, 0, 30, 0, 0, time.UTC),
+	}
+	p := NewSubscriptionProjector(subscriptionGetterFunc(
+		func(ctx context.Context, req *GetSubscriptionReq) (*Subscription, error) {
+			gets++
+			assert.Equal(t, "I-BW452GLLEP1G", req.ID)
+			return remote, nil
+		}), store)
+	var kinds []SubscriptionEventKind
+	p.OnEvent = func(ctx context.Context, e *SubscriptionEvent) error {
+		kinds = append(kinds, e.Kind)
+		return nil
+	}
+	r := NewWebhookRouter()
+	p.Register(r)
+	dispatch := func(wh *Webhook) {
+		t.Helper()
+		require.NoError(t, r.Dispatch(ctx, wh))
+	}
+	status := func() SubscriptionStatus {
+		s, err := store.LoadSubscription(ctx, "I-BW452GLLEP1G")
+		require.NoError(t, err)
+		return s.Status
+	}
+
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionCreated, SSApprovalPending, 1))
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionActivated, SSActive, 3))
+	// Out of order
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionCreated, SSApprovalPending, 1))
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionUpdated, SSApprovalPending, 2))
+	assert.Equal(t, SSActive, status())
+	assert.Equal(t, []SubscriptionEventKind{SEKCreated, SEKActivated}, kinds)
+
+	kinds = nil
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionPaymentFailed, SSActive, 4))
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionSuspended, SSSuspended, 5))
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionReActivated, SSActive, 6))
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionUpdated, SSActive, 7))
+	assert.Equal(t, []SubscriptionEventKind{
+		SEKPaymentFailed, SEKSuspended, SEKReactivated, SEKUpdated,
+	}, kinds)
+	assert.Zero(t, gets)
+
+	kinds = nil
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionCancelled, SSCancelled, 8))
+	// Invalid transition from CANCELLED, resolved by PayPal
+	remote.StatusUpdateTime = time.Date(2023, 1, 1, 0, 9, 0, 0, time.UTC)
+	remote.Status = SSCancelled
+	dispatch(newSubscriptionWebhook(t, BillingSubscriptionActivated, SSActive, 9))
+	assert.Equal(t, 1, gets)
+	assert.Equal(t, SSCancelled, status())
+	assert.Equal(t, []SubscriptionEventKind{SEKCancelled, SEKUpdated}, kinds)
+
+	kinds = nil
+	sale := &Sale{ID: "80021663DE681814L", State: SaleCompleted, BillingAgreementId: "I-BW452GLLEP1G"}
+	dispatch(newResourceWebhook(t, PaymentSaleCompleted, RTSale, sale))
+	dispatch(newResourceWebhook(t, PaymentSaleRefunded, RTSale, sale))
+	dispatch(newResourceWebhook(t, PaymentSaleCompleted, RTSale, &Sale{ID: "NOT-SUBSCRIPTION"}))
+	assert.Equal(t, []SubscriptionEventKind{SEKRenewed}, kinds)
+	assert.Equal(t, 1, gets)
+
+	// Unknown subscription of a sale
+	kinds = nil
+	remote = &Subscription{ID: "I-BW452GLLEP1G", Status: SSActive}
+	store = NewMemorySubscriptionStore()
+	p.Store = store
+	var sales []*Sale
+	p.OnEvent = func(ctx context.Context, e *SubscriptionEvent) error {
+		kinds = append(kinds, e.Kind)
+		sales = append(sales, e.Sale)
+		return nil
+	}
+	sale.State = SaleDenied
+	dispatch(newResourceWebhook(t, PaymentSaleDenied, RTSale, sale))
+	assert.Equal(t, 2, gets)
+	assert.Equal(t, SSActive, status())
+	assert.Equal(t, []SubscriptionEventKind{SEKPaymentFailed}, kinds)
+	assert.Equal(t, SaleDenied, sales[0].State)
+}
+
+func TestSubscriptionProjectorEventError(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySubscriptionStore()
+	p := NewSubscriptionProjector(nil, store)
+	p.OnEvent = func(ctx context.Context, e *SubscriptionEvent) error {
+		return fmt.Errorf("oops")
+	}
+	wh := newSubscriptionWebhook(t, BillingSubscriptionActivated, SSActive, 1)
+	assert.EqualError(t, p.Handle(ctx, wh), "on ACTIVATED event: oops")
+	s, err := store.LoadSubscription(ctx, "I-BW452GLLEP1G")
+	require.NoError(t, err)
+	assert.Nil(t, s, "not saved so that the redelivery emits the events again")
+}
+
+func TestSubscriptionProjectorLocking(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySubscriptionStore()
+	fetching, release := make(chan struct{}), make(chan struct{})
+	p := NewSubscriptionProjector(subscriptionGetterFunc(
+		func(ctx context.Context, req *GetSubscriptionReq) (*Subscription, error) {
+			close(fetching)
+			<-release
+			return &Subscription{ID: req.ID, Status: SSActive}, nil
+		}), store)
+
+	done := make(chan error)
+	go func() {
+		sale := &Sale{ID: "80021663DE681814L", State: SaleCompleted, BillingAgreementId: "I-SLOW"}
+		done <- p.Handle(ctx, newResourceWebhook(t, PaymentSaleCompleted, RTSale, sale))
+	}()
+	<-fetching
+
+	// Another subscription is projected while the first one is being fetched from PayPal
+	wh := newSubscriptionWebhook(t, BillingSubscriptionActivated, SSActive, 1)
+	require.NoError(t, p.Handle(ctx, wh))
+	assert.NotNil(t, ptesting.R(store.LoadSubscription(ctx, "I-BW452GLLEP1G")).NoError(t).V())
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.NotNil(t, ptesting.R(store.LoadSubscription(ctx, "I-SLOW")).NoError(t).V())
+	assert.Empty(t, p.locks)
+}
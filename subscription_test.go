@@ -10,9 +10,15 @@ import (
 )
 
 func TestSubscription(t *testing.T) {
-	c := NewTestClient()
+	c, fake := newTestClient(t)
 	ctx := context.Background()
 	subID := "I-SF5PBRMX4EPF"
+	if fake != nil {
+		sub := ptesting.R(c.CreateSubscription(ctx, &CreateSubscriptionReq{Subscription: &Subscription{
+			PlanID: "P-5ML4271244454362WXNWU5NQ",
+		}})).NoError(t).V()
+		subID = sub.ID
+	}
 	ptesting.R(c.GetSubscription(ctx, &GetSubscriptionReq{ID: subID})).NoError(t).
 		Do(func(t *testing.T, it *Subscription) {
 			t.Log(it)
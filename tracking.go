@@ -0,0 +1,209 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Carrier is the name of the carrier for the shipment.
+// Use [CarrierOther] with the carrier name set if the carrier is not listed.
+//
+// See https://developer.paypal.com/docs/tracking/reference/carriers/.
+type Carrier string
+
+const (
+	CarrierOther              Carrier = "OTHER"
+	CarrierDHL                Carrier = "DHL"
+	CarrierDHLAPI             Carrier = "DHL_API"
+	CarrierDHLGlobalMail      Carrier = "DHL_GLOBAL_MAIL"
+	CarrierFedEx              Carrier = "FEDEX"
+	CarrierUPS                Carrier = "UPS"
+	CarrierUPSMailInnovations Carrier = "UPS_MI"
+	CarrierUSPS               Carrier = "USPS"
+	CarrierAmazon             Carrier = "AMAZON"
+	CarrierAramex             Carrier = "ARAMEX"
+	CarrierAustraliaPost      Carrier = "AUSTRALIA_POST"
+	CarrierCanadaPost         Carrier = "CANADA_POST"
+	CarrierChinaPost          Carrier = "CHINA_POST"
+	CarrierColissimo          Carrier = "COLISSIMO"
+	CarrierDeutschePost       Carrier = "DEUTSCHE_DE"
+	CarrierDPD                Carrier = "DPD"
+	CarrierGLS                Carrier = "GLS"
+	CarrierHermes             Carrier = "HERMES"
+	CarrierJapanPost          Carrier = "JAPAN_POST"
+	CarrierLaPoste            Carrier = "LAPOSTE"
+	CarrierPostNL             Carrier = "POSTNL"
+	CarrierRoyalMail          Carrier = "ROYAL_MAIL"
+	CarrierSFExpress          Carrier = "SF_EXPRESS"
+	CarrierTNT                Carrier = "TNT"
+	CarrierYamato             Carrier = "YAMATO"
+	CarrierYodel              Carrier = "YODEL"
+)
+
+// UPC is the Universal Product Code of the item.
+type UPC struct {
+	// Type is the UPC type, e.g. UPC-A.
+	Type string `json:"type"`
+	Code string `json:"code"`
+}
+
+// TrackingItem is an item in the shipment.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#orders_track_create!path=items&t=request.
+type TrackingItem struct {
+	Name     string `json:"name,omitempty"`
+	Quantity string `json:"quantity,omitempty"`
+	SKU      string `json:"sku,omitempty"`
+	URL      string `json:"url,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	UPC      *UPC   `json:"upc,omitempty"`
+}
+
+type AddTrackingReq struct {
+	OrderID string `json:"-"`
+
+	// CaptureID is the PayPal capture ID,
+	// see [Order.Captures] of the order returned by [Client.CaptureOrder].
+	CaptureID        string          `json:"capture_id"`      // Required
+	TrackingNumber   string          `json:"tracking_number"` // Required
+	Carrier          Carrier         `json:"carrier"`         // Required
+	CarrierNameOther string          `json:"carrier_name_other,omitempty"`
+	NotifyPayer      bool            `json:"notify_payer,omitempty"`
+	Items            []*TrackingItem `json:"items,omitempty"`
+}
+
+// AddTracking adds tracking information for an order.
+// The returned order contains the tracker in the shipping of the purchase unit.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#orders_track_create.
+func (c *Client) AddTracking(ctx context.Context, req *AddTrackingReq) (res *Order, err error) {
+	ctx = WithOperation(ctx, "AddTracking")
+	return JSON[Order](ctx, c, http.MethodPost, "/v2/checkout/orders/"+req.OrderID+"/track", req)
+}
+
+type TrackerStatus string
+
+const (
+	TSShipped     TrackerStatus = "SHIPPED"
+	TSOnHold      TrackerStatus = "ON_HOLD"
+	TSDelivered   TrackerStatus = "DELIVERED"
+	TSCancelled   TrackerStatus = "CANCELLED"
+	TSLocalPickup TrackerStatus = "LOCAL_PICKUP"
+)
+
+// OrderTracker is the tracker of an order shipment.
+type OrderTracker struct {
+	// ID is the tracker ID, which is in the format of "{capture_id}-{tracking_number}".
+	ID         string          `json:"id,omitempty"`
+	Status     TrackerStatus   `json:"status,omitempty"`
+	Items      []*TrackingItem `json:"items,omitempty"`
+	Links      []*Link         `json:"links,omitempty"`
+	CreateTime time.Time       `json:"create_time,omitempty"`
+	UpdateTime time.Time       `json:"update_time,omitempty"`
+}
+
+// CaptureID returns the ID of the capture which the tracker belongs to.
+func (t *OrderTracker) CaptureID() string {
+	id, _, _ := strings.Cut(t.ID, "-")
+	return id
+}
+
+// Patch is a JSON patch operation.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#definition-patch.
+type Patch struct {
+	Op    string `json:"op"` // add, remove, replace, move, copy or test
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+	From  string `json:"from,omitempty"`
+}
+
+type UpdateTrackingReq struct {
+	OrderID   string
+	TrackerID string // See [OrderTracker.ID]
+	Patches   []*Patch
+}
+
+// UpdateTracking updates or cancels the tracking information of an order.
+//
+// See https://developer.paypal.com/docs/api/orders/v2/#orders_trackers_patch.
+func (c *Client) UpdateTracking(ctx context.Context, req *UpdateTrackingReq) (err error) {
+	ctx = WithOperation(ctx, "UpdateTracking")
+	path := "/v2/checkout/orders/" + req.OrderID + "/trackers/" + req.TrackerID
+	return JSONNop(ctx, c, http.MethodPatch, path, req.Patches)
+}
+
+// Tracker is the tracking information of a shipment for a PayPal transaction,
+// which is the capture ID for Orders v2.
+//
+// See https://developer.paypal.com/docs/api/tracking/v1/#definition-tracker.
+type Tracker struct {
+	TransactionID    string          `json:"transaction_id"` // Required
+	TrackingNumber   string          `json:"tracking_number,omitempty"`
+	Status           TrackerStatus   `json:"status"` // Required
+	Carrier          Carrier         `json:"carrier,omitempty"`
+	CarrierNameOther string          `json:"carrier_name_other,omitempty"`
+	NotifyBuyer      bool            `json:"notify_buyer,omitempty"`
+	ShipmentDate     string          `json:"shipment_date,omitempty"` // YYYY-MM-DD
+	Items            []*TrackingItem `json:"items,omitempty"`
+	Links            []*Link         `json:"links,omitempty"`
+}
+
+// TrackerID returns the ID of the tracker used in [Client.GetTracker] and [Client.UpdateTracker].
+func (t *Tracker) TrackerID() string {
+	return t.TransactionID + "-" + t.TrackingNumber
+}
+
+type AddTrackersReq struct {
+	Trackers []*Tracker `json:"trackers"`
+}
+
+// TrackerIdentifier identifies a tracker.
+type TrackerIdentifier struct {
+	TransactionID  string  `json:"transaction_id,omitempty"`
+	TrackingNumber string  `json:"tracking_number,omitempty"`
+	Links          []*Link `json:"links,omitempty"`
+}
+
+// TrackersBatch is the result of [Client.AddTrackers].
+type TrackersBatch struct {
+	TrackerIdentifiers []*TrackerIdentifier `json:"tracker_identifiers,omitempty"`
+	// Errors of the failed trackers, the batch request succeeds partially.
+	Errors []*Error `json:"errors,omitempty"`
+	Links  []*Link  `json:"links,omitempty"`
+}
+
+// AddTrackers adds tracking information for multiple PayPal transactions.
+//
+// See https://developer.paypal.com/docs/api/tracking/v1/#trackers-batch_post.
+func (c *Client) AddTrackers(ctx context.Context, req *AddTrackersReq,
+) (res *TrackersBatch, err error) {
+	ctx = WithOperation(ctx, "AddTrackers")
+	return JSON[TrackersBatch](ctx, c, http.MethodPost, "/v1/shipping/trackers-batch", req)
+}
+
+type GetTrackerReq struct {
+	ID string // See [Tracker.TrackerID]
+}
+
+// GetTracker shows tracking information for a tracker ID.
+//
+// See https://developer.paypal.com/docs/api/tracking/v1/#trackers_get.
+func (c *Client) GetTracker(ctx context.Context, req *GetTrackerReq) (res *Tracker, err error) {
+	ctx = WithOperation(ctx, "GetTracker")
+	return JSON[Tracker](ctx, c, http.MethodGet, "/v1/shipping/trackers/"+req.ID, nil)
+}
+
+type UpdateTrackerReq struct {
+	*Tracker
+}
+
+// UpdateTracker updates or cancels the tracking information for a PayPal transaction.
+//
+// See https://developer.paypal.com/docs/api/tracking/v1/#trackers_put.
+func (c *Client) UpdateTracker(ctx context.Context, req *UpdateTrackerReq) (err error) {
+	ctx = WithOperation(ctx, "UpdateTracker")
+	return JSONNop(ctx, c, http.MethodPut, "/v1/shipping/trackers/"+req.TrackerID(), req)
+}
@@ -0,0 +1,132 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestTracking(t *testing.T) {
+	ctx := context.Background()
+	captured := &Order{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "5O190127TN364715T",
+		"status": "COMPLETED",
+		"purchase_units": [
+			{
+				"payments": {
+					"captures": [
+						{
+							"id": "3C679366HH908993F",
+							"status": "COMPLETED",
+							"amount": {"currency_code": "USD", "value": "100.00"},
+							"final_capture": true
+						}
+					]
+				}
+			}
+		]
+	}`), captured))
+	captureID := captured.Captures()[0].ID
+	assert.Equal(t, "3C679366HH908993F", captureID)
+
+	t.Run("Add", func(t *testing.T) {
+		h := ServeJSON(t, http.MethodPost, "/v2/checkout/orders/5O190127TN364715T/track", 201, `{
+			"id": "5O190127TN364715T",
+			"status": "COMPLETED",
+			"purchase_units": [
+				{
+					"shipping": {
+						"trackers": [
+							{
+								"id": "3C679366HH908993F-443844607820",
+								"status": "SHIPPED",
+								"items": [{"name": "T-Shirt", "quantity": "1", "sku": "sku02"}]
+							}
+						]
+					}
+				}
+			]
+		}`)
+		c := NewMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := ptesting.R(io.ReadAll(r.Body)).NoError(t).V()
+			assert.JSONEq(t, `{
+				"capture_id": "3C679366HH908993F",
+				"tracking_number": "443844607820",
+				"carrier": "FEDEX",
+				"notify_payer": true,
+				"items": [{"name": "T-Shirt", "quantity": "1", "sku": "sku02"}]
+			}`, string(body))
+			h.ServeHTTP(w, r)
+		}))
+		req := &AddTrackingReq{
+			OrderID:        captured.ID,
+			CaptureID:      captureID,
+			TrackingNumber: "443844607820",
+			Carrier:        CarrierFedEx,
+			NotifyPayer:    true,
+			Items:          []*TrackingItem{{Name: "T-Shirt", Quantity: "1", SKU: "sku02"}},
+		}
+		ptesting.R(c.AddTracking(ctx, req)).NoError(t).Do(func(t *testing.T, it *Order) {
+			tracker := it.PurchaseUnits[0].Shipping.Trackers[0]
+			assert.Equal(t, TSShipped, tracker.Status)
+			assert.Equal(t, captureID, tracker.CaptureID())
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		path := "/v2/checkout/orders/5O190127TN364715T/trackers/3C679366HH908993F-443844607820"
+		c := NewMockClient(t, ServeJSON(t, http.MethodPatch, path, 204, ""))
+		req := &UpdateTrackingReq{
+			OrderID:   captured.ID,
+			TrackerID: "3C679366HH908993F-443844607820",
+			Patches:   []*Patch{{Op: "replace", Path: "/status", Value: TSCancelled}},
+		}
+		assert.NoError(t, c.UpdateTracking(ctx, req))
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		c := NewMockClient(t, ServeJSON(t, http.MethodPost, "/v1/shipping/trackers-batch", 200, `{
+			"tracker_identifiers": [
+				{"transaction_id": "8MC585209K746392H", "tracking_number": "443844607820"}
+			],
+			"errors": [
+				{"name": "RESOURCE_NOT_FOUND", "message": "The specified resource does not exist."}
+			]
+		}`))
+		req := &AddTrackersReq{
+			Trackers: []*Tracker{
+				{TransactionID: "8MC585209K746392H", TrackingNumber: "443844607820",
+					Status: TSShipped, Carrier: CarrierFedEx},
+				{TransactionID: "53Y56775AE587553X", TrackingNumber: "443844607821",
+					Status: TSShipped, Carrier: CarrierOther, CarrierNameOther: "Local"},
+			},
+		}
+		ptesting.R(c.AddTrackers(ctx, req)).NoError(t).Do(func(t *testing.T, it *TrackersBatch) {
+			assert.Len(t, it.TrackerIdentifiers, 1)
+			assert.Equal(t, "RESOURCE_NOT_FOUND", it.Errors[0].Name)
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		path := "/v1/shipping/trackers/8MC585209K746392H-443844607820"
+		c := NewMockClient(t, ServeJSON(t, http.MethodGet, path, 200, `{
+			"transaction_id": "8MC585209K746392H",
+			"tracking_number": "443844607820",
+			"status": "SHIPPED",
+			"carrier": "FEDEX"
+		}`))
+		req := &GetTrackerReq{ID: "8MC585209K746392H-443844607820"}
+		ptesting.R(c.GetTracker(ctx, req)).NoError(t).Do(func(t *testing.T, it *Tracker) {
+			assert.Equal(t, CarrierFedEx, it.Carrier)
+			assert.Equal(t, req.ID, it.TrackerID())
+		})
+	})
+}
@@ -2,18 +2,42 @@ package paypal
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"time"
 )
 
 type Webhook struct {
-	ID           string         `json:"id,omitempty"`
-	CreateTime   time.Time      `json:"create_time,omitempty"`
-	ResourceType string         `json:"resource_type,omitempty"`
-	EventType    EventType      `json:"event_type,omitempty"`
-	Summary      string         `json:"summary,omitempty"`
-	Resource     map[string]any `json:"resource,omitempty"`
-	Links        []Link         `json:"links,omitempty"`
+	ID              string    `json:"id,omitempty"`
+	CreateTime      time.Time `json:"create_time,omitempty"`
+	ResourceType    string    `json:"resource_type,omitempty"`
+	ResourceVersion string    `json:"resource_version,omitempty"`
+	EventType       EventType `json:"event_type,omitempty"`
+	EventVersion    string    `json:"event_version,omitempty"`
+	Summary         string    `json:"summary,omitempty"`
+	// Resource is the resource of the event, see [Webhook.Decode].
+	Resource map[string]any `json:"resource,omitempty"`
+	Links    []Link         `json:"links,omitempty"`
+}
+
+// RawResource returns the resource in JSON format, marshaled from Resource.
+func (wh *Webhook) RawResource() (json.RawMessage, error) {
+	return json.Marshal(wh.Resource)
+}
+
+// ResourceID returns the ID of the resource, or empty if absent.
+func (wh *Webhook) ResourceID() string {
+	id, _ := wh.Resource["id"].(string)
+	return id
+}
+
+// decodeResource decodes the resource of the webhook into v.
+func (wh *Webhook) decodeResource(v any) error {
+	bs, err := wh.RawResource()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
 }
 
 type VerifyWSReq struct {
@@ -23,6 +47,10 @@ type VerifyWSReq struct {
 	TransmissionTime time.Time `json:"transmission_time,omitempty"`
 	TransmissionSig  string    `json:"transmission_sig,omitempty"`
 
+	// RawTransmissionTime is the raw PAYPAL-TRANSMISSION-TIME header, which is what PayPal signs,
+	// see [SignedString]. It is set by [NewVerifyWSReq].
+	RawTransmissionTime string `json:"-"`
+
 	// WebhookID is the ID of webhook as configured in your Developer Portal account.
 	WebhookID    string `json:"webhook_id,omitempty"`
 	WebhookEvent any    `json:"webhook_event,omitempty"`
@@ -45,3 +73,23 @@ func (c *Client) VerifyWebhookSign(ctx context.Context, req *VerifyWSReq,
 	}
 	return r.VerificationStatus == "SUCCESS", nil
 }
+
+type SimulateWebhookEventReq struct {
+	// WebhookID is the ID of the webhook to send the event to,
+	// URL is used instead if empty.
+	WebhookID string    `json:"webhook_id,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	EventType EventType `json:"event_type"`
+	// ResourceVersion is the version of the resource, e.g. "2.0".
+	ResourceVersion string `json:"resource_version,omitempty"`
+}
+
+// SimulateWebhookEvent sends a sample event of the type to the webhook or the URL.
+// The simulated events cannot be verified by the PayPal verification API.
+//
+// See https://developer.paypal.com/docs/api/webhooks/v1/#simulate-event_post
+func (c *Client) SimulateWebhookEvent(ctx context.Context, req *SimulateWebhookEventReq,
+) (res *Webhook, err error) {
+	ctx = WithOperation(ctx, "SimulateWebhookEvent")
+	return JSON[Webhook](ctx, c, http.MethodPost, "/v1/notifications/simulate-event", req)
+}
@@ -89,7 +89,7 @@ const (
 	//
 	// Related method: [Capture authorized payment] with `status` of `declined`.
 	//
-	// [Capture authorized payment]: https://developer.paypal.comhttps://developer.paypal.com/docs/api/payments/v2/#authorizations_capture
+	// [Capture authorized payment]: https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture
 	PaymentCaptureDeclined EventType = "PAYMENT.CAPTURE.DECLINED"
 
 	// A payment capture completes.
@@ -228,28 +228,28 @@ const (
 	// Related method: [Create billing plan]
 	//
 	// [Create billing plan]: https://developer.paypal.com/docs/api/payments.billing-plans/v1/#billing-plans_post
-	BillingPlanCreated EventType = "BILLING.PLAN.CREATED"
+	// (redeclared) BillingPlanCreated EventType = "BILLING.PLAN.CREATED"
 
 	// A billing plan is updated.
 	//
 	// Related method: [Update billing plan]
 	//
 	// [Update billing plan]: https://developer.paypal.com/docs/api/payments.billing-plans/v1/#billing-plans_patch
-	BillingPlanUpdated EventType = "BILLING.PLAN.UPDATED"
+	// (redeclared) BillingPlanUpdated EventType = "BILLING.PLAN.UPDATED"
 
 	// A billing agreement is canceled.
 	//
 	// Related method: [Cancel agreement]
 	//
 	// [Cancel agreement]: https://developer.paypal.com/docs/api/payments.billing-agreements/v1/#billing-agreements_cancel
-	BillingSubscriptionCancelled EventType = "BILLING.SUBSCRIPTION.CANCELLED"
+	// (redeclared) BillingSubscriptionCancelled EventType = "BILLING.SUBSCRIPTION.CANCELLED"
 
 	// A billing agreement is created.
 	//
 	// Related method: [Create agreement]
 	//
 	// [Create agreement]: https://developer.paypal.com/docs/api/payments.billing-agreements/v1/#billing-agreements_re-activate
-	BillingSubscriptionCreated EventType = "BILLING.SUBSCRIPTION.CREATED"
+	// (redeclared) BillingSubscriptionCreated EventType = "BILLING.SUBSCRIPTION.CREATED"
 
 	// A billing agreement is re-activated.
 	//
@@ -263,14 +263,14 @@ const (
 	// Related method: [Suspend agreement]
 	//
 	// [Suspend agreement]: https://developer.paypal.com/docs/api/payments.billing-agreements/v1/#billing-agreements_suspend
-	BillingSubscriptionSuspended EventType = "BILLING.SUBSCRIPTION.SUSPENDED"
+	// (redeclared) BillingSubscriptionSuspended EventType = "BILLING.SUBSCRIPTION.SUSPENDED"
 
 	// A billing agreement is updated.
 	//
 	// Related method: [Update agreement]
 	//
 	// [Update agreement]: https://developer.paypal.com/docs/api/payments.billing-agreements/v1/#billing-agreements_patch
-	BillingSubscriptionUpdated EventType = "BILLING.SUBSCRIPTION.UPDATED"
+	// (redeclared) BillingSubscriptionUpdated EventType = "BILLING.SUBSCRIPTION.UPDATED"
 )
 
 
@@ -769,14 +769,14 @@ const (
 	// Related method: [Create plan]
 	//
 	// [Create plan]: https://developer.paypal.com/docs/api/subscriptions/v1/#plans_create
-	// (redeclared) BillingPlanCreated EventType = "BILLING.PLAN.CREATED"
+	BillingPlanCreated EventType = "BILLING.PLAN.CREATED"
 
 	// A billing plan is updated.
 	//
 	// Related method: [Update plan]
 	//
 	// [Update plan]: https://developer.paypal.com/docs/api/subscriptions/v1/#plans_patch
-	// (redeclared) BillingPlanUpdated EventType = "BILLING.PLAN.UPDATED"
+	BillingPlanUpdated EventType = "BILLING.PLAN.UPDATED"
 
 	// A billing plan is activated.
 	//
@@ -804,7 +804,7 @@ const (
 	// Related method: [Create subscription]
 	//
 	// [Create subscription]: https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_create
-	// (redeclared) BillingSubscriptionCreated EventType = "BILLING.SUBSCRIPTION.CREATED"
+	BillingSubscriptionCreated EventType = "BILLING.SUBSCRIPTION.CREATED"
 
 	// A subscription is activated.
 	//
@@ -818,7 +818,7 @@ const (
 	// Related method: [Update subscription]
 	//
 	// [Update subscription]: https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_patch
-	// (redeclared) BillingSubscriptionUpdated EventType = "BILLING.SUBSCRIPTION.UPDATED"
+	BillingSubscriptionUpdated EventType = "BILLING.SUBSCRIPTION.UPDATED"
 
 	// A subscription expires.
 	//
@@ -832,14 +832,14 @@ const (
 	// Related method: [Cancel subscription]
 	//
 	// [Cancel subscription]: https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_cancel
-	// (redeclared) BillingSubscriptionCancelled EventType = "BILLING.SUBSCRIPTION.CANCELLED"
+	BillingSubscriptionCancelled EventType = "BILLING.SUBSCRIPTION.CANCELLED"
 
 	// A subscription is suspended.
 	//
 	// Related method: [Suspend subscription]
 	//
 	// [Suspend subscription]: https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_suspend
-	// (redeclared) BillingSubscriptionSuspended EventType = "BILLING.SUBSCRIPTION.SUSPENDED"
+	BillingSubscriptionSuspended EventType = "BILLING.SUBSCRIPTION.SUSPENDED"
 
 	// Payment failed on subscription.
 	//
@@ -872,3 +872,517 @@ const (
 
 // Additional information
 
+
+// eventTypeInfos is the metadata of the event types.
+var eventTypeInfos = map[EventType]*eventTypeInfo{
+	PaymentAuthorizationCreated: {
+		group:            "Payments",
+		versions:         []string{"V1", "V2"},
+		description:      "A payment authorization is created, approved, executed, or a future payment authorization is created.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+	},
+	PaymentAuthorizationVoided: {
+		group:            "Payments",
+		versions:         []string{"V1", "V2"},
+		description:      "A payment authorization is voided either due to authorization reaching it’s 30 day validity period or authorization was manually voided using the Void Authorized Payment API.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_get",
+	},
+	PaymentCaptureCompleted: {
+		group:            "Payments",
+		versions:         []string{"V1", "V2"},
+		description:      "A payment capture completes.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+	},
+	PaymentCaptureDenied: {
+		group:            "Payments",
+		versions:         []string{"V1"},
+		description:      "A payment capture is denied.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#capture_get",
+	},
+	PaymentCapturePending: {
+		group:            "Payments",
+		versions:         []string{"V1", "V2"},
+		description:      "The state of a payment capture changes to pending.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+	},
+	PaymentCaptureRefunded: {
+		group:            "Payments",
+		versions:         []string{"V1", "V2"},
+		description:      "A merchant refunds a payment capture.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+	},
+	PaymentCaptureReversed: {
+		group:            "Payments",
+		versions:         []string{"V1", "V2"},
+		description:      "PayPal reverses a payment capture.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#captures_refund",
+	},
+	PaymentCaptureDeclined: {
+		group:            "Payments",
+		versions:         []string{"V2"},
+		description:      "A payment capture is declined.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+	},
+	PaymentPayoutsbatchDenied: {
+		group:            "Batch payouts",
+		description:      "A batch payout payment is denied.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_get",
+	},
+	PaymentPayoutsbatchProcessing: {
+		group:            "Batch payouts",
+		description:      "The state of a batch payout payment changes to processing.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_get",
+	},
+	PaymentPayoutsbatchSuccess: {
+		group:            "Batch payouts",
+		description:      "A batch payout payment completes successfully.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_get",
+	},
+	PaymentPayoutsItemBlocked: {
+		group:            "Batch payouts",
+		description:      "A payouts item is blocked.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemCanceled: {
+		group:            "Batch payouts",
+		description:      "A payouts item is canceled.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_cancel",
+	},
+	PaymentPayoutsItemDenied: {
+		group:            "Batch payouts",
+		description:      "A payouts item is denied.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemFailed: {
+		group:            "Batch payouts",
+		description:      "A payouts item fails.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemHeld: {
+		group:            "Batch payouts",
+		description:      "A payouts item is held.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemRefunded: {
+		group:            "Batch payouts",
+		description:      "A payouts item is refunded.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemReturned: {
+		group:            "Batch payouts",
+		description:      "A payouts item is returned.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemSucceeded: {
+		group:            "Batch payouts",
+		description:      "A payouts item succeeds.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	PaymentPayoutsItemUnclaimed: {
+		group:            "Batch payouts",
+		description:      "A payouts item is unclaimed.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get",
+	},
+	BillingPlanCreated: {
+		group:            "Subscriptions",
+		description:      "A billing plan is created.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#plans_create",
+	},
+	BillingPlanUpdated: {
+		group:            "Subscriptions",
+		description:      "A billing plan is updated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#plans_patch",
+	},
+	BillingSubscriptionCancelled: {
+		group:            "Subscriptions",
+		description:      "A subscription is cancelled.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_cancel",
+	},
+	BillingSubscriptionCreated: {
+		group:            "Subscriptions",
+		description:      "A subscription is created.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_create",
+	},
+	BillingSubscriptionReActivated: {
+		group:            "Billing plans and agreements",
+		description:      "A billing agreement is re-activated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments.billing-agreements/v1/#billing-agreements_re-activate",
+		deprecated:       true,
+	},
+	BillingSubscriptionSuspended: {
+		group:            "Subscriptions",
+		description:      "A subscription is suspended.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_suspend",
+	},
+	BillingSubscriptionUpdated: {
+		group:            "Subscriptions",
+		description:      "A subscription is updated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_patch",
+	},
+	IdentityAuthorizationConsentRevoked: {
+		group:            "Log in with PayPal",
+		description:      "A user's consent token is revoked.",
+	},
+	PaymentsPaymentCreated: {
+		group:            "Checkout buyer approval",
+		description:      "Checkout payment is created and approved by buyer.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#payment_get",
+	},
+	CheckoutOrderApproved: {
+		group:            "Checkout buyer approval",
+		versions:         []string{"V2"},
+		description:      "A buyer approved a checkout order",
+		relatedMethodURL: "https://developer.paypal.com/api/rest/webhooks/event-names/#orders",
+	},
+	CheckoutCheckoutBuyerApproved: {
+		group:            "Checkout buyer approval",
+		description:      "Express checkout payment is created and approved by buyer.",
+		relatedMethodURL: "https://developer.paypal.com/api/nvp-soap/payflow/express-checkout/sale/#the-express-checkout-basic-integration",
+	},
+	CustomerDisputeCreated: {
+		group:            "Disputes",
+		description:      "A dispute is created.",
+	},
+	CustomerDisputeResolved: {
+		group:            "Disputes",
+		description:      "A dispute is resolved.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes-actions_adjudicate",
+	},
+	CustomerDisputeUpdated: {
+		group:            "Disputes",
+		description:      "A dispute is updated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_patch",
+	},
+	RiskDisputeCreated: {
+		group:            "Disputes",
+		description:      "A risk dispute is created.",
+		deprecated:       true,
+	},
+	InvoicingInvoiceCancelled: {
+		group:            "Invoicing",
+		description:      "A merchant or customer cancels an invoice.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/invoicing/v1/#invoices_get",
+	},
+	InvoicingInvoiceCreated: {
+		group:            "Invoicing",
+		description:      "An invoice is created.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/invoicing/v1/#invoices_create",
+	},
+	InvoicingInvoicePaid: {
+		group:            "Invoicing",
+		description:      "An invoice is paid, partially paid, or payment is made and is pending.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/invoicing/v1/#invoices_record-payment",
+	},
+	InvoicingInvoiceRefunded: {
+		group:            "Invoicing",
+		description:      "An invoice is refunded or partially refunded.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/invoicing/v1/#invoices_record-refund",
+	},
+	InvoicingInvoiceScheduled: {
+		group:            "Invoicing",
+		description:      "An invoice is scheduled.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/invoicing/v1/#invoices_schedule",
+	},
+	InvoicingInvoiceUpdated: {
+		group:            "Invoicing",
+		description:      "An invoice is updated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/invoicing/v1/#invoices_update",
+	},
+	CheckoutOrderCompleted: {
+		group:            "Orders",
+		versions:         []string{"V2"},
+		description:      "A checkout order is processed. <strong>Note:</strong> For use by marketplaces and platforms only.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/orders/v2/",
+	},
+	CheckoutOrderProcessed: {
+		group:            "Orders",
+		versions:         []string{"V1"},
+		description:      "A checkout order is processed.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/orders/v1/",
+	},
+	CustomerAccountLimitationAdded: {
+		group:            "Marketplaces and Platforms",
+		description:      "A limitation is added for a partner's managed account.",
+		relatedMethodURL: "https://developer.paypal.com/api/limited-release/managed-accounts/v3",
+	},
+	CustomerAccountLimitationEscalated: {
+		group:            "Marketplaces and Platforms",
+		description:      "A limitation is escalated for a partner's managed account.",
+		relatedMethodURL: "https://developer.paypal.com/api/limited-release/managed-accounts/v3",
+	},
+	CustomerAccountLimitationLifted: {
+		group:            "Marketplaces and Platforms",
+		description:      "A limitation is lifted for a partner's managed account.",
+		relatedMethodURL: "https://developer.paypal.com/api/limited-release/managed-accounts/v3",
+	},
+	CustomerAccountLimitationUpdated: {
+		group:            "Marketplaces and Platforms",
+		description:      "A limitation is updated for a partner's managed account.",
+		relatedMethodURL: "https://developer.paypal.com/api/limited-release/managed-accounts/v3",
+	},
+	CustomerMerchantIntegrationCapabilityUpdated: {
+		group:            "Marketplaces and Platforms",
+		description:      "PayPal must enable the merchant's account as `PPCP` for this webhook to work.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create",
+	},
+	CustomerMerchantIntegrationProductSubscriptionUpdated: {
+		group:            "Marketplaces and Platforms",
+		description:      "The products available to the merchant have changed.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create",
+	},
+	CustomerMerchantIntegrationSellerAlreadyIntegrated: {
+		group:            "Marketplaces and Platforms",
+		description:      "Merchant onboards again to a partner.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create",
+	},
+	CustomerMerchantIntegrationSellerOnboardingInitiated: {
+		group:            "Marketplaces and Platforms",
+		description:      "PayPal creates a merchant account from the partner's onboarding link.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create",
+	},
+	CustomerMerchantIntegrationSellerConsentGranted: {
+		group:            "Marketplaces and Platforms",
+		description:      "Merchant grants consents to a partner.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create",
+	},
+	CustomerMerchantIntegrationSellerEmailConfirmed: {
+		group:            "Marketplaces and Platforms",
+		description:      "Merchant confirms the email and consents are granted.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create",
+	},
+	MerchantOnboardingCompleted: {
+		group:            "Marketplaces and Platforms",
+		description:      "Merchant completes setup.",
+		relatedMethodURL: "https://developer.paypal.com#merchant-onboarding",
+	},
+	MerchantPartnerConsentRevoked: {
+		group:            "Marketplaces and Platforms",
+		description:      "The consents for a merchant account setup are revoked or an account is closed.",
+		relatedMethodURL: "https://developer.paypal.com#merchant-onboarding",
+	},
+	PaymentReferencedPayoutItemCompleted: {
+		group:            "Marketplaces and Platforms",
+		description:      "Funds are disbursed to the seller and partner.",
+		relatedMethodURL: "https://developer.paypal.com#authorized-and-captured-payments",
+	},
+	PaymentReferencedPayoutItemFailed: {
+		group:            "Marketplaces and Platforms",
+		description:      "Attempt to disburse funds fails.",
+		relatedMethodURL: "https://developer.paypal.com#authorized-and-captured-payments",
+	},
+	CustomerManagedAccountAccountCreated: {
+		group:            "Merchant onboarding",
+		description:      "Managed account has been created.",
+		relatedMethodURL: "https://developer.paypal.com/limited-release/commerce-platform/v3/seller-onboarding/managed-seller-onboarding",
+	},
+	CustomerManagedAccountCreationFailed: {
+		group:            "Merchant onboarding",
+		description:      "Managed account creation failed.",
+		relatedMethodURL: "https://developer.paypal.com/limited-release/commerce-platform/v3/seller-onboarding/managed-seller-onboarding",
+	},
+	CustomerManagedAccountAccountUpdated: {
+		group:            "Merchant onboarding",
+		description:      "Managed account has been updated.",
+		relatedMethodURL: "https://developer.paypal.com/limited-release/commerce-platform/v3/seller-onboarding/managed-seller-onboarding",
+	},
+	CustomerManagedAccountAccountStatusChanged: {
+		group:            "Merchant onboarding",
+		description:      "Capabilities and/or process status has been changed on a managed account.",
+		relatedMethodURL: "https://developer.paypal.com/limited-release/commerce-platform/v3/seller-onboarding/managed-seller-onboarding",
+	},
+	CustomerManagedAccountRiskAssessed: {
+		group:            "Merchant onboarding",
+		description:      "Managed account has been risk assessed or the risk assessment has been changed.",
+		relatedMethodURL: "https://developer.paypal.com/limited-release/commerce-platform/v3/seller-onboarding/managed-seller-onboarding",
+	},
+	CustomerManagedAccountNegativeBalanceNotified: {
+		group:            "Merchant onboarding",
+		description:      "Negative balance debit has been notified on a managed account.",
+		relatedMethodURL: "https://developer.paypal.com/api/limited-release/managed-accounts/v3",
+	},
+	CustomerManagedAccountNegativeBalanceDebitInitiated: {
+		group:            "Merchant onboarding",
+		description:      "Negative balance debit has been initiated on a managed account.",
+		relatedMethodURL: "https://developer.paypal.com/api/limited-release/managed-accounts/v3",
+	},
+	CheckoutPaymentApprovalReversed: {
+		group:            "Orders",
+		versions:         []string{"V2"},
+		description:      "A problem occurred after the buyer approved the order but before you captured the payment. Refer to [Handle uncaptured payments] for what to do when this event occurs.",
+		relatedMethodURL: "https://developer.paypal.com/docs/checkout/apm/reference/handle-uncaptured-payments/",
+	},
+	PaymentOrderCancelled: {
+		group:            "Payment orders",
+		description:      "A payment order is canceled.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#orders_void",
+	},
+	PaymentOrderCreated: {
+		group:            "Payment orders",
+		description:      "A payment order is created.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+	},
+	PaymentSaleCompleted: {
+		group:            "Sales",
+		description:      "A sale completes.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#sale_get",
+	},
+	PaymentSaleDenied: {
+		group:            "Sales",
+		description:      "The state of a sale changes from pending to denied.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#sale_get",
+	},
+	PaymentSalePending: {
+		group:            "Sales",
+		description:      "The state of a sale changes to pending.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#sale_get",
+	},
+	PaymentSaleRefunded: {
+		group:            "Sales",
+		description:      "A merchant refunds a sale.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#sale_refund",
+	},
+	PaymentSaleReversed: {
+		group:            "Sales",
+		description:      "PayPal reverses a sale.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/payments/v1/#sale_refund",
+	},
+	CatalogProductCreated: {
+		group:            "Subscriptions",
+		description:      "A product is created.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/catalog-products/v1/#products_create",
+	},
+	CatalogProductUpdated: {
+		group:            "Subscriptions",
+		description:      "A product is updated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/catalog-products/v1/#products_patch",
+	},
+	BillingPlanActivated: {
+		group:            "Subscriptions",
+		description:      "A billing plan is activated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#plans_activate",
+	},
+	BillingPlanPricingChangeActivated: {
+		group:            "Subscriptions",
+		description:      "A price change for the plan is activated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#plans_update-pricing-schemes",
+	},
+	BillingPlanDeactivated: {
+		group:            "Subscriptions",
+		description:      "A billing plan is deactivated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#plans_deactivate",
+	},
+	BillingSubscriptionActivated: {
+		group:            "Subscriptions",
+		description:      "A subscription is activated.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_activate",
+	},
+	BillingSubscriptionExpired: {
+		group:            "Subscriptions",
+		description:      "A subscription expires.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_get",
+	},
+	BillingSubscriptionPaymentFailed: {
+		group:            "Subscriptions",
+		description:      "Payment failed on subscription.",
+		relatedMethodURL: "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions-get-response",
+	},
+	VaultCreditCardCreated: {
+		group:            "Vault",
+		description:      "A credit card is created.",
+	},
+	VaultCreditCardDeleted: {
+		group:            "Vault",
+		description:      "A credit card is deleted.",
+	},
+	VaultCreditCardUpdated: {
+		group:            "Vault",
+		description:      "A credit card is updated.",
+	},
+}
+
+// allEventTypes is all the event types in the order of declaration.
+var allEventTypes = []EventType{
+	PaymentAuthorizationCreated,
+	PaymentAuthorizationVoided,
+	PaymentCaptureCompleted,
+	PaymentCaptureDenied,
+	PaymentCapturePending,
+	PaymentCaptureRefunded,
+	PaymentCaptureReversed,
+	PaymentCaptureDeclined,
+	PaymentPayoutsbatchDenied,
+	PaymentPayoutsbatchProcessing,
+	PaymentPayoutsbatchSuccess,
+	PaymentPayoutsItemBlocked,
+	PaymentPayoutsItemCanceled,
+	PaymentPayoutsItemDenied,
+	PaymentPayoutsItemFailed,
+	PaymentPayoutsItemHeld,
+	PaymentPayoutsItemRefunded,
+	PaymentPayoutsItemReturned,
+	PaymentPayoutsItemSucceeded,
+	PaymentPayoutsItemUnclaimed,
+	BillingPlanCreated,
+	BillingPlanUpdated,
+	BillingSubscriptionCancelled,
+	BillingSubscriptionCreated,
+	BillingSubscriptionReActivated,
+	BillingSubscriptionSuspended,
+	BillingSubscriptionUpdated,
+	IdentityAuthorizationConsentRevoked,
+	PaymentsPaymentCreated,
+	CheckoutOrderApproved,
+	CheckoutCheckoutBuyerApproved,
+	CustomerDisputeCreated,
+	CustomerDisputeResolved,
+	CustomerDisputeUpdated,
+	RiskDisputeCreated,
+	InvoicingInvoiceCancelled,
+	InvoicingInvoiceCreated,
+	InvoicingInvoicePaid,
+	InvoicingInvoiceRefunded,
+	InvoicingInvoiceScheduled,
+	InvoicingInvoiceUpdated,
+	CheckoutOrderCompleted,
+	CheckoutOrderProcessed,
+	CustomerAccountLimitationAdded,
+	CustomerAccountLimitationEscalated,
+	CustomerAccountLimitationLifted,
+	CustomerAccountLimitationUpdated,
+	CustomerMerchantIntegrationCapabilityUpdated,
+	CustomerMerchantIntegrationProductSubscriptionUpdated,
+	CustomerMerchantIntegrationSellerAlreadyIntegrated,
+	CustomerMerchantIntegrationSellerOnboardingInitiated,
+	CustomerMerchantIntegrationSellerConsentGranted,
+	CustomerMerchantIntegrationSellerEmailConfirmed,
+	MerchantOnboardingCompleted,
+	MerchantPartnerConsentRevoked,
+	PaymentReferencedPayoutItemCompleted,
+	PaymentReferencedPayoutItemFailed,
+	CustomerManagedAccountAccountCreated,
+	CustomerManagedAccountCreationFailed,
+	CustomerManagedAccountAccountUpdated,
+	CustomerManagedAccountAccountStatusChanged,
+	CustomerManagedAccountRiskAssessed,
+	CustomerManagedAccountNegativeBalanceNotified,
+	CustomerManagedAccountNegativeBalanceDebitInitiated,
+	CheckoutPaymentApprovalReversed,
+	PaymentOrderCancelled,
+	PaymentOrderCreated,
+	PaymentSaleCompleted,
+	PaymentSaleDenied,
+	PaymentSalePending,
+	PaymentSaleRefunded,
+	PaymentSaleReversed,
+	CatalogProductCreated,
+	CatalogProductUpdated,
+	BillingPlanActivated,
+	BillingPlanPricingChangeActivated,
+	BillingPlanDeactivated,
+	BillingSubscriptionActivated,
+	BillingSubscriptionExpired,
+	BillingSubscriptionPaymentFailed,
+	VaultCreditCardCreated,
+	VaultCreditCardDeleted,
+	VaultCreditCardUpdated,
+}
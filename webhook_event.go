@@ -0,0 +1,63 @@
+package paypal
+
+import "github.com/adobaai/paypal/internal/webhook"
+
+// eventTypeInfo is the metadata of an event type generated by paypalc.
+type eventTypeInfo struct {
+	group            string
+	versions         []string
+	description      string
+	relatedMethodURL string
+	deprecated       bool
+}
+
+func (et EventType) info() *eventTypeInfo {
+	if info, ok := eventTypeInfos[et]; ok {
+		return info
+	}
+	return &eventTypeInfo{}
+}
+
+// Valid reports whether the event type is a known PayPal event type.
+func (et EventType) Valid() bool {
+	_, ok := eventTypeInfos[et]
+	return ok
+}
+
+// Group returns the group of the event type in the PayPal documents, e.g. "Payments".
+func (et EventType) Group() string {
+	return et.info().group
+}
+
+// Versions returns the API versions of the event type, e.g. ["V1", "V2"],
+// or nil if the group of the event type is not versioned.
+func (et EventType) Versions() []string {
+	return append([]string(nil), et.info().versions...)
+}
+
+// Deprecated reports whether the event type is deprecated.
+func (et EventType) Deprecated() bool {
+	return et.info().deprecated
+}
+
+// Description returns the description of the event trigger.
+func (et EventType) Description() string {
+	return et.info().description
+}
+
+// RelatedMethodURL returns the document URL of the related API method, or "" if none.
+func (et EventType) RelatedMethodURL() string {
+	return et.info().relatedMethodURL
+}
+
+// ResourceType returns the resource type of the webhooks of the event type,
+// e.g. [RTCapture] for "PAYMENT.CAPTURE.COMPLETED",
+// which is the lowercase second segment of the event type if not known.
+func (et EventType) ResourceType() string {
+	return webhook.ResourceType(string(et))
+}
+
+// AllEventTypes returns all the known PayPal event types in the order of the PayPal documents.
+func AllEventTypes() []EventType {
+	return append([]EventType(nil), allEventTypes...)
+}
@@ -0,0 +1,51 @@
+package paypal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventType(t *testing.T) {
+	et := PaymentCaptureCompleted
+	assert.True(t, et.Valid())
+	assert.Equal(t, "Payments", et.Group())
+	assert.Equal(t, []string{"V1", "V2"}, et.Versions())
+	assert.False(t, et.Deprecated())
+	assert.Equal(t, "A payment capture completes.", et.Description())
+	assert.Equal(t, "https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture",
+		et.RelatedMethodURL())
+
+	assert.True(t, BillingSubscriptionReActivated.Deprecated())
+	// The event types redeclared by the subscriptions are not deprecated
+	for _, et := range []EventType{BillingSubscriptionActivated, BillingSubscriptionCancelled,
+		BillingSubscriptionCreated, BillingPlanCreated} {
+		assert.False(t, et.Deprecated(), et)
+		assert.Equal(t, "Subscriptions", et.Group(), et)
+	}
+	assert.Equal(t, "https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_create",
+		BillingSubscriptionCreated.RelatedMethodURL())
+	assert.True(t, RiskDisputeCreated.Deprecated())
+	assert.Nil(t, BillingSubscriptionActivated.Versions())
+
+	assert.Equal(t, RTCapture, et.ResourceType())
+	assert.Equal(t, RTCheckoutOrder, CheckoutOrderApproved.ResourceType())
+	assert.Equal(t, RTSubscription, BillingSubscriptionActivated.ResourceType())
+	assert.Equal(t, RTPayoutsItem, PaymentPayoutsItemDenied.ResourceType())
+	assert.Equal(t, "plan", BillingPlanCreated.ResourceType())
+
+	unknown := EventType("PAYMENT.UNKNOWN")
+	assert.False(t, unknown.Valid())
+	assert.Zero(t, unknown.Group())
+
+	all := AllEventTypes()
+	assert.Equal(t, PaymentAuthorizationCreated, all[0])
+	assert.Contains(t, all, IdentityAuthorizationConsentRevoked)
+	seen := map[EventType]bool{}
+	for _, et := range all {
+		assert.True(t, et.Valid(), et)
+		assert.NotZero(t, et.Description(), et)
+		assert.False(t, seen[et], "duplicate %s", et)
+		seen[et] = true
+	}
+}
@@ -0,0 +1,283 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/adobaai/paypal/internal/webhook"
+)
+
+// Headers of the webhook requests sent by PayPal.
+//
+// See https://developer.paypal.com/api/rest/webhooks/rest/#link-eventheaders.
+const (
+	HeaderTransmissionID   = webhook.HeaderTransmissionID
+	HeaderTransmissionTime = webhook.HeaderTransmissionTime
+	HeaderTransmissionSig  = webhook.HeaderTransmissionSig
+	HeaderCertURL          = webhook.HeaderCertURL
+	HeaderAuthAlgo         = webhook.HeaderAuthAlgo
+)
+
+// DefaultMaxWebhookBytes is the default limit of the webhook request body size.
+const DefaultMaxWebhookBytes = 1 << 20
+
+// WebhookVerifier verifies the signature of webhooks.
+// It is implemented by [Client], which calls the PayPal verification API,
+// and [CertVerifier], which verifies the signature locally.
+//
+// An invalid signature is reported by returning false,
+// or an error wrapping [ErrInvalidWebhookSign] with the reason.
+type WebhookVerifier interface {
+	VerifyWebhookSign(ctx context.Context, req *VerifyWSReq) (ok bool, err error)
+}
+
+// WebhookVerifierFunc is an adapter to allow the use of ordinary functions as [WebhookVerifier].
+type WebhookVerifierFunc func(ctx context.Context, req *VerifyWSReq) (ok bool, err error)
+
+func (f WebhookVerifierFunc) VerifyWebhookSign(ctx context.Context, req *VerifyWSReq,
+) (ok bool, err error) {
+	return f(ctx, req)
+}
+
+// NewVerifyWSReq returns a new [VerifyWSReq] with the headers and the raw body
+// of the webhook request.
+// The WebhookEvent of the result is the raw body as [json.RawMessage].
+func NewVerifyWSReq(header http.Header, body []byte, webhookID string,
+) (res *VerifyWSReq, err error) {
+	res = &VerifyWSReq{
+		AuthAlgo:        header.Get(HeaderAuthAlgo),
+		CertURL:         header.Get(HeaderCertURL),
+		TransmissionID:  header.Get(HeaderTransmissionID),
+		TransmissionSig: header.Get(HeaderTransmissionSig),
+		WebhookID:       webhookID,
+		WebhookEvent:    json.RawMessage(body),
+	}
+	for _, k := range []string{HeaderAuthAlgo, HeaderCertURL, HeaderTransmissionID, HeaderTransmissionSig} {
+		if header.Get(k) == "" {
+			return nil, fmt.Errorf("missing header: %s", k)
+		}
+	}
+	res.RawTransmissionTime = header.Get(HeaderTransmissionTime)
+	if res.TransmissionTime, err = time.Parse(time.RFC3339, res.RawTransmissionTime); err != nil {
+		return nil, fmt.Errorf("invalid header %s: %w", HeaderTransmissionTime, err)
+	}
+	return
+}
+
+// VerifyOutcome is the outcome of the webhook signature verification.
+type VerifyOutcome string
+
+const (
+	// VOVerified indicates the signature is valid.
+	VOVerified VerifyOutcome = "VERIFIED"
+	// VOFailed indicates the signature is invalid.
+	VOFailed VerifyOutcome = "FAILED"
+	// VOError indicates the verification could not complete, e.g. a network error.
+	VOError VerifyOutcome = "ERROR"
+)
+
+// WebhookFunc handles a verified webhook.
+// Returning an error makes PayPal redeliver the webhook later.
+type WebhookFunc func(ctx context.Context, wh *Webhook) error
+
+// WebhookHandler is an [http.Handler] for PayPal webhooks.
+// It verifies the signature of each request, decodes the [Webhook],
+// and dispatches it to the function registered for the event type.
+//
+// The status codes of the responses follow the PayPal retry semantics,
+// PayPal redelivers the webhook on any non-2xx responses:
+//
+//   - 200 if the webhook is handled or there is no function for the event type.
+//   - 4xx if the request is malformed or the signature is invalid.
+//   - 5xx if the verification could not complete or the function returns an error.
+//
+// See https://developer.paypal.com/api/rest/webhooks/rest/.
+type WebhookHandler struct {
+	Verifier WebhookVerifier
+	// WebhookID is the ID of webhook as configured in your Developer Portal account.
+	WebhookID string
+	// MaxBodyBytes limits the request body size, [DefaultMaxWebhookBytes] is used if zero.
+	MaxBodyBytes int64
+	// OnVerify is called after each verification if not nil, e.g. to record metrics.
+	OnVerify func(ctx context.Context, req *VerifyWSReq, outcome VerifyOutcome, err error)
+	// Guard rejects the replayed webhooks if not nil.
+	// The stale webhooks are responded with 400,
+	// and the duplicate ones are acknowledged with 200 without being dispatched.
+	Guard *ReplayGuard
+	// Router dispatches the verified webhooks, see [WebhookRouter] for the patterns.
+	Router *WebhookRouter
+	// Queue queues the verified webhooks for asynchronous handling if not nil,
+	// instead of dispatching them with the Router before responding.
+	// The webhooks failed to be queued are responded with 503.
+	Queue WebhookQueue
+	// Store persists the verified webhooks if not nil before they are handled,
+	// so that they can be replayed later, see [ReplayWebhooks].
+	// The ones rejected by the Guard, i.e. the stale and the duplicate ones, are not stored.
+	// The webhooks failed to be stored are responded with 503.
+	Store WebhookStore
+}
+
+// NewWebhookHandler returns a new [WebhookHandler]
+// verifying webhooks with the verifier, which is usually a [Client].
+func NewWebhookHandler(v WebhookVerifier, webhookID string) *WebhookHandler {
+	return &WebhookHandler{
+		Verifier:  v,
+		WebhookID: webhookID,
+		Router:    NewWebhookRouter(),
+	}
+}
+
+// Handle registers the function for the event type.
+// Use [WebhookRouter.Handle] of the Router to register wildcard patterns.
+func (h *WebhookHandler) Handle(et EventType, f WebhookFunc) {
+	h.router().Handle(string(et), f)
+}
+
+// HandleDefault registers the function for the event types without a registered function.
+func (h *WebhookHandler) HandleDefault(f WebhookFunc) {
+	h.router().Handle("*", f)
+}
+
+func (h *WebhookHandler) router() *WebhookRouter {
+	if h.Router == nil {
+		h.Router = NewWebhookRouter()
+	}
+	return h.Router
+}
+
+// WebhookError is the error of handling a webhook request,
+// with the HTTP status code of the response.
+type WebhookError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("%d %s: %v", e.StatusCode, http.StatusText(e.StatusCode), e.Err)
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+func webhookErr(code int, format string, args ...any) error {
+	return &WebhookError{StatusCode: code, Err: fmt.Errorf(format, args...)}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.serve(r); err != nil {
+		code := http.StatusInternalServerError
+		var we *WebhookError
+		if errors.As(err, &we) {
+			code = we.StatusCode
+		}
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) serve(r *http.Request) error {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		return webhookErr(http.StatusMethodNotAllowed, "method: %s", r.Method)
+	}
+
+	limit := h.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxWebhookBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return webhookErr(http.StatusBadRequest, "read body: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return webhookErr(http.StatusRequestEntityTooLarge, "body exceeds %d bytes", limit)
+	}
+
+	vreq, err := NewVerifyWSReq(r.Header, body, h.WebhookID)
+	if err != nil {
+		return &WebhookError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	if h.Guard != nil {
+		if err = h.Guard.CheckTime(vreq); err != nil {
+			return &WebhookError{StatusCode: http.StatusBadRequest, Err: err}
+		}
+	}
+	if err = h.verify(ctx, vreq); err != nil {
+		return err
+	}
+
+	wh := new(Webhook)
+	if err = json.Unmarshal(body, wh); err != nil {
+		return webhookErr(http.StatusBadRequest, "unmarshal: %w", err)
+	}
+	if h.Guard != nil {
+		err = h.Guard.CheckSeen(ctx, vreq, wh)
+		if errors.Is(err, ErrWebhookDuplicate) {
+			// Acknowledge so that PayPal stops redelivering
+			return nil
+		}
+		if err != nil {
+			return &WebhookError{StatusCode: http.StatusServiceUnavailable, Err: err}
+		}
+	}
+	if err = h.handle(ctx, r, body, wh); err != nil && h.Guard != nil {
+		if ferr := h.Guard.Forget(ctx, vreq, wh); ferr != nil {
+			err = fmt.Errorf("%w (forget: %v)", err, ferr)
+		}
+	}
+	return err
+}
+
+// handle stores, enqueues or dispatches the verified webhook.
+func (h *WebhookHandler) handle(ctx context.Context, r *http.Request, body []byte, wh *Webhook,
+) (err error) {
+	if h.Store != nil {
+		if err = h.Store.Append(ctx, NewStoredWebhook(r.Header, body, time.Now())); err != nil {
+			return webhookErr(http.StatusServiceUnavailable, "store: %w", err)
+		}
+	}
+	if h.Queue != nil {
+		if err = h.Queue.Enqueue(ctx, wh); err != nil {
+			return webhookErr(http.StatusServiceUnavailable, "enqueue: %w", err)
+		}
+		return nil
+	}
+	return h.dispatch(ctx, wh)
+}
+
+func (h *WebhookHandler) verify(ctx context.Context, req *VerifyWSReq) (err error) {
+	ok, err := h.Verifier.VerifyWebhookSign(ctx, req)
+	outcome := VOVerified
+	switch {
+	case errors.Is(err, ErrInvalidWebhookSign):
+		outcome = VOFailed
+		err = &WebhookError{StatusCode: http.StatusUnauthorized, Err: err}
+	case err != nil:
+		outcome = VOError
+		err = webhookErr(http.StatusServiceUnavailable, "verify: %w", err)
+	case !ok:
+		outcome = VOFailed
+		err = webhookErr(http.StatusUnauthorized, "invalid signature")
+	}
+	if h.OnVerify != nil {
+		h.OnVerify(ctx, req, outcome, err)
+	}
+	return
+}
+
+func (h *WebhookHandler) dispatch(ctx context.Context, wh *Webhook) error {
+	if h.Router == nil {
+		return nil
+	}
+	if err := h.Router.Dispatch(ctx, wh); err != nil {
+		return fmt.Errorf("handle %s %s: %w", wh.EventType, wh.ID, err)
+	}
+	return nil
+}
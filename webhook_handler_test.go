@@ -0,0 +1,147 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookBody = `{
+	"id": "WH-2WR32451HC0233532-67976317FL4543714",
+	"create_time": "2014-10-23T17:23:52Z",
+	"resource_type": "capture",
+	"event_type": "PAYMENT.CAPTURE.COMPLETED",
+	"summary": "A successful sale payment was made for $ 0.48 USD",
+	"resource": {
+		"id": "42311647XV020574X",
+		"status": "COMPLETED",
+		"amount": {"currency_code": "USD", "value": "0.48"}
+	}
+}`
+
+func newWebhookRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	r.Header.Set(HeaderAuthAlgo, "SHA256withRSA")
+	r.Header.Set(HeaderCertURL, "https://api.sandbox.paypal.com/v1/notifications/certs/CERT-360caa42-fca2a594-a5cafa77")
+	r.Header.Set(HeaderTransmissionID, "103e3700-8b0c-11e6-8695-6b62a8a99ac4")
+	r.Header.Set(HeaderTransmissionSig, "t8hlRk64rpEImZMKqgtp5dlWaT1W8ed/mf8Msos341QInVn3BMQubjAhM/cxAw==")
+	r.Header.Set(HeaderTransmissionTime, "2016-10-05T14:54:46Z")
+	return r
+}
+
+func serveWebhook(h http.Handler, r *http.Request) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestWebhookHandler(t *testing.T) {
+	verified := WebhookVerifierFunc(func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+		assert.Equal(t, "WEBHOOK-ID", req.WebhookID)
+		assert.Equal(t, "103e3700-8b0c-11e6-8695-6b62a8a99ac4", req.TransmissionID)
+		assert.Equal(t, "2016-10-05T14:54:46Z", req.TransmissionTime.Format("2006-01-02T15:04:05Z07:00"))
+		assert.JSONEq(t, testWebhookBody, string(req.WebhookEvent.(json.RawMessage)))
+		return true, nil
+	})
+	var outcomes []VerifyOutcome
+	newHandler := func(v WebhookVerifier) *WebhookHandler {
+		h := NewWebhookHandler(v, "WEBHOOK-ID")
+		h.OnVerify = func(ctx context.Context, req *VerifyWSReq, o VerifyOutcome, err error) {
+			outcomes = append(outcomes, o)
+		}
+		return h
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		h := newHandler(verified)
+		var got *Webhook
+		h.Handle(PaymentCaptureCompleted, func(ctx context.Context, wh *Webhook) error {
+			got = wh
+			return nil
+		})
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		require.NotNil(t, got)
+		assert.Equal(t, "WH-2WR32451HC0233532-67976317FL4543714", got.ID)
+		assert.Equal(t, "42311647XV020574X", got.Resource["id"])
+	})
+
+	t.Run("Unhandled", func(t *testing.T) {
+		h := newHandler(verified)
+		h.Handle(PaymentCaptureDenied, func(ctx context.Context, wh *Webhook) error {
+			t.Error("unexpected call")
+			return nil
+		})
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+
+		var called bool
+		h.HandleDefault(func(ctx context.Context, wh *Webhook) error {
+			called = true
+			return nil
+		})
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		assert.True(t, called)
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		h := newHandler(verified)
+		h.HandleDefault(func(ctx context.Context, wh *Webhook) error {
+			return errors.New("db down")
+		})
+		assert.Equal(t, http.StatusInternalServerError,
+			serveWebhook(h, newWebhookRequest(testWebhookBody)))
+	})
+
+	t.Run("BadRequest", func(t *testing.T) {
+		h := newHandler(verified)
+		r := newWebhookRequest(testWebhookBody)
+		r.Method = http.MethodGet
+		assert.Equal(t, http.StatusMethodNotAllowed, serveWebhook(h, r))
+
+		r = newWebhookRequest(testWebhookBody)
+		r.Header.Del(HeaderTransmissionSig)
+		assert.Equal(t, http.StatusBadRequest, serveWebhook(h, r))
+
+		r = newWebhookRequest(testWebhookBody)
+		r.Header.Set(HeaderTransmissionTime, "yesterday")
+		assert.Equal(t, http.StatusBadRequest, serveWebhook(h, r))
+
+		h.MaxBodyBytes = 16
+		assert.Equal(t, http.StatusRequestEntityTooLarge,
+			serveWebhook(h, newWebhookRequest(testWebhookBody)))
+	})
+
+	t.Run("Verification", func(t *testing.T) {
+		outcomes = nil
+		h := newHandler(WebhookVerifierFunc(func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+			return false, nil
+		}))
+		assert.Equal(t, http.StatusUnauthorized, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+
+		h = newHandler(WebhookVerifierFunc(func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+			return false, errors.New("timeout")
+		}))
+		assert.Equal(t, http.StatusServiceUnavailable,
+			serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		assert.Equal(t, []VerifyOutcome{VOFailed, VOError}, outcomes)
+	})
+}
+
+func TestNewVerifyWSReq(t *testing.T) {
+	// The first missing header is reported in a fixed order
+	header := newWebhookRequest(testWebhookBody).Header
+	header.Del(HeaderTransmissionSig)
+	header.Del(HeaderCertURL)
+	for i := 0; i < 10; i++ {
+		_, err := NewVerifyWSReq(header, []byte(testWebhookBody), "WEBHOOK-ID")
+		assert.EqualError(t, err, "missing header: "+HeaderCertURL)
+	}
+	_, err := NewVerifyWSReq(http.Header{}, nil, "WEBHOOK-ID")
+	assert.EqualError(t, err, "missing header: "+HeaderAuthAlgo)
+}
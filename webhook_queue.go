@@ -0,0 +1,200 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull indicates the webhook queue has no room for more webhooks.
+	ErrQueueFull = errors.New("webhook queue full")
+	// ErrQueueClosed indicates the webhook queue no longer accepts webhooks.
+	ErrQueueClosed = errors.New("webhook queue closed")
+)
+
+const (
+	// DefaultQueueWorkers is the default number of workers of [MemoryQueue].
+	DefaultQueueWorkers = 8
+	// DefaultQueueSize is the default buffer size of each worker of [MemoryQueue].
+	DefaultQueueSize = 100
+	// DefaultMaxAttempts is the default max number of attempts to handle a webhook.
+	DefaultMaxAttempts = 5
+)
+
+// WebhookQueue queues the verified webhooks for asynchronous handling,
+// so that the [WebhookHandler] can acknowledge PayPal quickly.
+// Implementations backed by a durable storage (e.g. a database or a message broker)
+// are needed to survive restarts.
+type WebhookQueue interface {
+	// Enqueue queues the webhook, the webhook is redelivered by PayPal if it fails.
+	Enqueue(ctx context.Context, wh *Webhook) error
+}
+
+// MemoryQueue is an in-process bounded [WebhookQueue] with a pool of workers.
+//
+// The webhooks of the same resource ID are handled in order by the same worker.
+// The failed ones are retried with backoff up to MaxAttempts,
+// and then passed to DeadLetter.
+//
+// The fields must not be changed after [MemoryQueue.Start].
+type MemoryQueue struct {
+	// Func handles the webhooks, e.g. [WebhookRouter.Dispatch]. Panics are handled as errors.
+	Func WebhookFunc
+	// Workers is the number of workers, [DefaultQueueWorkers] is used if zero.
+	Workers int
+	// Size is the buffer size of each worker, [DefaultQueueSize] is used if zero.
+	Size int
+	// MaxAttempts is the max number of attempts, [DefaultMaxAttempts] is used if zero.
+	MaxAttempts int
+	// Backoff returns the delay before the attempt, which starts from 2.
+	// The default is [ExponentialBackoff] from 1s to 1m.
+	Backoff func(attempt int) time.Duration
+	// DeadLetter is called with the last error if not nil
+	// when a webhook fails all the attempts, e.g. to save it for manual handling.
+	DeadLetter func(ctx context.Context, wh *Webhook, err error)
+
+	mu     sync.RWMutex
+	shards []chan *Webhook
+	closed bool
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewMemoryQueue returns a new [MemoryQueue] handling webhooks with the function.
+func NewMemoryQueue(f WebhookFunc) *MemoryQueue {
+	return &MemoryQueue{Func: f}
+}
+
+// ExponentialBackoff returns a backoff function doubling the delay from min up to max.
+func ExponentialBackoff(min, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := min
+		for i := 2; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// Start starts the workers.
+func (q *MemoryQueue) Start() {
+	workers, size := q.Workers, q.Size
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	if q.MaxAttempts <= 0 {
+		q.MaxAttempts = DefaultMaxAttempts
+	}
+	if q.Backoff == nil {
+		q.Backoff = ExponentialBackoff(time.Second, time.Minute)
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	q.shards = make([]chan *Webhook, workers)
+	for i := range q.shards {
+		q.shards[i] = make(chan *Webhook, size)
+		q.wg.Add(1)
+		go q.work(q.shards[i])
+	}
+}
+
+// Enqueue queues the webhook without blocking.
+// An error wrapping [ErrQueueFull] is returned if the worker of the resource is busy,
+// or [ErrQueueClosed] if the queue is closed.
+func (q *MemoryQueue) Enqueue(_ context.Context, wh *Webhook) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed || q.shards == nil {
+		return ErrQueueClosed
+	}
+	key := wh.ResourceID()
+	if key == "" {
+		key = wh.ID
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	select {
+	case q.shards[h.Sum32()%uint32(len(q.shards))] <- wh:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s %s", ErrQueueFull, wh.EventType, wh.ID)
+	}
+}
+
+// Close stops accepting webhooks and waits for the queued ones to be handled.
+// If the context is done first, the pending retries are abandoned to DeadLetter
+// and the context error is returned.
+func (q *MemoryQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed || q.shards == nil {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	for _, ch := range q.shards {
+		close(ch)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (q *MemoryQueue) work(ch <-chan *Webhook) {
+	defer q.wg.Done()
+	f := RecoverWebhook()(q.Func)
+	for wh := range ch {
+		q.handle(f, wh)
+	}
+}
+
+func (q *MemoryQueue) handle(f WebhookFunc, wh *Webhook) {
+	var err error
+	for attempt := 1; attempt <= q.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			t := time.NewTimer(q.Backoff(attempt))
+			select {
+			case <-t.C:
+			case <-q.ctx.Done():
+				t.Stop()
+				q.deadLetter(wh, fmt.Errorf("handle %s %s abandoned after %d attempts: %w",
+					wh.EventType, wh.ID, attempt-1, err))
+				return
+			}
+		}
+		if err = f(q.ctx, wh); err == nil {
+			return
+		}
+	}
+	q.deadLetter(wh, fmt.Errorf("handle %s %s after %d attempts: %w",
+		wh.EventType, wh.ID, q.MaxAttempts, err))
+}
+
+func (q *MemoryQueue) deadLetter(wh *Webhook, err error) {
+	if q.DeadLetter != nil {
+		// The queue context may be canceled
+		q.DeadLetter(context.Background(), wh, err)
+	}
+}
@@ -0,0 +1,161 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueueWebhook(id, resourceID string) *Webhook {
+	return &Webhook{
+		ID:        id,
+		EventType: PaymentCaptureCompleted,
+		Resource:  map[string]any{"id": resourceID},
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(time.Second, 5*time.Second)
+	var got []time.Duration
+	for i := 2; i <= 6; i++ {
+		got = append(got, b(i))
+	}
+	assert.Equal(t, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second,
+	}, got)
+}
+
+func TestMemoryQueue(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		handled  = map[string][]string{}
+		attempts = map[string]int{}
+		dead     []error
+	)
+	q := NewMemoryQueue(func(ctx context.Context, wh *Webhook) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[wh.ID]++
+		switch wh.ID {
+		case "WH-FLAKY":
+			if attempts[wh.ID] < 3 {
+				return errors.New("flaky")
+			}
+		case "WH-POISON":
+			panic("poison")
+		}
+		handled[wh.ResourceID()] = append(handled[wh.ResourceID()], wh.ID)
+		return nil
+	})
+	q.Workers = 4
+	q.MaxAttempts = 3
+	q.Backoff = func(int) time.Duration { return time.Millisecond }
+	q.DeadLetter = func(ctx context.Context, wh *Webhook, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dead = append(dead, err)
+	}
+
+	ctx := context.Background()
+	assert.ErrorIs(t, q.Enqueue(ctx, newQueueWebhook("WH-0", "R0")), ErrQueueClosed)
+	q.Start()
+	for i := 0; i < 20; i++ {
+		require.NoError(t, q.Enqueue(ctx, newQueueWebhook(fmt.Sprintf("WH-%02d", i), fmt.Sprint("R", i%3))))
+	}
+	require.NoError(t, q.Enqueue(ctx, newQueueWebhook("WH-FLAKY", "R-FLAKY")))
+	require.NoError(t, q.Enqueue(ctx, newQueueWebhook("WH-POISON", "R-POISON")))
+	require.NoError(t, q.Close(ctx))
+	assert.ErrorIs(t, q.Enqueue(ctx, newQueueWebhook("WH-0", "R0")), ErrQueueClosed)
+
+	for i := 0; i < 3; i++ {
+		var want []string
+		for j := i; j < 20; j += 3 {
+			want = append(want, fmt.Sprintf("WH-%02d", j))
+		}
+		assert.Equal(t, want, handled[fmt.Sprint("R", i)])
+	}
+	assert.Equal(t, []string{"WH-FLAKY"}, handled["R-FLAKY"])
+	assert.Equal(t, 3, attempts["WH-FLAKY"])
+	assert.Equal(t, 3, attempts["WH-POISON"])
+	require.Len(t, dead, 1)
+	assert.ErrorContains(t, dead[0], "WH-POISON after 3 attempts: panic: poison")
+}
+
+func TestMemoryQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	q := NewMemoryQueue(func(ctx context.Context, wh *Webhook) error {
+		<-block
+		return nil
+	})
+	q.Workers = 1
+	q.Size = 1
+	q.Start()
+	ctx := context.Background()
+	// One is being handled and the other is buffered
+	require.NoError(t, q.Enqueue(ctx, newQueueWebhook("WH-1", "R")))
+	require.Eventually(t, func() bool {
+		return q.Enqueue(ctx, newQueueWebhook("WH-2", "R")) == nil
+	}, time.Second, time.Millisecond)
+	assert.ErrorIs(t, q.Enqueue(ctx, newQueueWebhook("WH-3", "R")), ErrQueueFull)
+	close(block)
+	require.NoError(t, q.Close(ctx))
+}
+
+func TestMemoryQueueCloseTimeout(t *testing.T) {
+	var dead []error
+	q := NewMemoryQueue(func(ctx context.Context, wh *Webhook) error {
+		return errors.New("oops")
+	})
+	q.Workers = 1
+	q.Backoff = func(int) time.Duration { return time.Hour }
+	q.DeadLetter = func(ctx context.Context, wh *Webhook, err error) {
+		dead = append(dead, err)
+	}
+	q.Start()
+	require.NoError(t, q.Enqueue(context.Background(), newQueueWebhook("WH-1", "R")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.Close(ctx), context.DeadlineExceeded)
+	require.Len(t, dead, 1)
+	assert.ErrorContains(t, dead[0], "WH-1 abandoned after 1 attempts: oops")
+}
+
+func TestWebhookHandlerQueue(t *testing.T) {
+	verified := WebhookVerifierFunc(func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+		return true, nil
+	})
+	h := NewWebhookHandler(verified, "WEBHOOK-ID")
+	h.HandleDefault(func(ctx context.Context, wh *Webhook) error {
+		t.Error("dispatched synchronously")
+		return nil
+	})
+	var queued []*Webhook
+	full := false
+	h.Queue = webhookQueueFunc(func(ctx context.Context, wh *Webhook) error {
+		if full {
+			return ErrQueueFull
+		}
+		queued = append(queued, wh)
+		return nil
+	})
+	assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+	require.Len(t, queued, 1)
+	assert.Equal(t, "42311647XV020574X", queued[0].ResourceID())
+
+	full = true
+	assert.Equal(t, http.StatusServiceUnavailable, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+}
+
+type webhookQueueFunc func(ctx context.Context, wh *Webhook) error
+
+func (f webhookQueueFunc) Enqueue(ctx context.Context, wh *Webhook) error {
+	return f(ctx, wh)
+}
@@ -0,0 +1,196 @@
+package paypal
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrWebhookStale indicates the transmission time is out of the allowed window.
+	ErrWebhookStale = errors.New("webhook transmission time out of window")
+	// ErrWebhookDuplicate indicates the transmission or the event was already received.
+	ErrWebhookDuplicate = errors.New("duplicate webhook")
+)
+
+const (
+	// DefaultMaxSkew is the default max difference between the transmission time and now.
+	DefaultMaxSkew = 5 * time.Minute
+	// DefaultSeenTTL is the default duration to remember the received webhooks,
+	// which covers the 3 days PayPal keeps redelivering a webhook.
+	DefaultSeenTTL = 72 * time.Hour
+	// DefaultSeenCapacity is the default capacity of [MemorySeenStore].
+	DefaultSeenCapacity = 100_000
+)
+
+// SeenStore remembers keys for a while to detect duplicates.
+// Implementations backed by a shared storage (e.g. Redis SET NX EX) are needed
+// when there are multiple instances of the webhook handler.
+type SeenStore interface {
+	// Seen records the key for the TTL and reports whether the key was already recorded.
+	Seen(ctx context.Context, key string, ttl time.Duration) (seen bool, err error)
+	// Forget removes the key, e.g. when the webhook failed to be handled
+	// so that its redelivery is not a duplicate.
+	Forget(ctx context.Context, key string) error
+}
+
+// MemorySeenStore is an in-memory LRU [SeenStore],
+// the least recently used keys are evicted when the capacity is exceeded.
+type MemorySeenStore struct {
+	capacity int
+	now      func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type seenItem struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemorySeenStore returns a new [MemorySeenStore] with the capacity,
+// [DefaultSeenCapacity] is used if the capacity is not positive.
+func NewMemorySeenStore(capacity int) *MemorySeenStore {
+	if capacity <= 0 {
+		capacity = DefaultSeenCapacity
+	}
+	return &MemorySeenStore{
+		capacity: capacity,
+		now:      time.Now,
+		ll:       list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+func (s *MemorySeenStore) Seen(_ context.Context, key string, ttl time.Duration,
+) (seen bool, err error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.items[key]; ok {
+		it := e.Value.(*seenItem)
+		seen = now.Before(it.expiresAt)
+		if !seen {
+			it.expiresAt = now.Add(ttl)
+		}
+		s.ll.MoveToFront(e)
+		return
+	}
+
+	s.items[key] = s.ll.PushFront(&seenItem{key: key, expiresAt: now.Add(ttl)})
+	for s.ll.Len() > s.capacity {
+		e := s.ll.Back()
+		s.ll.Remove(e)
+		delete(s.items, e.Value.(*seenItem).key)
+	}
+	return
+}
+
+func (s *MemorySeenStore) Forget(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[key]; ok {
+		s.ll.Remove(e)
+		delete(s.items, key)
+	}
+	return nil
+}
+
+// Len returns the number of keys in the store, including the expired ones not evicted yet.
+func (s *MemorySeenStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+// ReplayGuard protects the webhook handler from replay attacks.
+// It rejects the webhooks whose transmission time is out of the window,
+// and the duplicate transmission IDs and event IDs,
+// so that each webhook is handled successfully at most once.
+type ReplayGuard struct {
+	// MaxSkew is the max difference between the transmission time and now,
+	// [DefaultMaxSkew] is used if zero.
+	MaxSkew time.Duration
+	// TTL is the duration to remember the received webhooks, [DefaultSeenTTL] is used if zero.
+	TTL   time.Duration
+	Store SeenStore
+	// Now returns the current time, [time.Now] is used if nil.
+	Now func() time.Time
+}
+
+// NewReplayGuard returns a new [ReplayGuard] with the store,
+// a [MemorySeenStore] is used if the store is nil.
+func NewReplayGuard(store SeenStore) *ReplayGuard {
+	if store == nil {
+		store = NewMemorySeenStore(0)
+	}
+	return &ReplayGuard{Store: store}
+}
+
+// CheckTime returns an error wrapping [ErrWebhookStale]
+// if the transmission time is out of the window.
+func (g *ReplayGuard) CheckTime(req *VerifyWSReq) error {
+	skew := g.MaxSkew
+	if skew <= 0 {
+		skew = DefaultMaxSkew
+	}
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+	d := now.Sub(req.TransmissionTime)
+	if d > skew || d < -skew {
+		return fmt.Errorf("%w: %s", ErrWebhookStale, req.TransmissionTime)
+	}
+	return nil
+}
+
+// CheckSeen records the transmission ID and the event ID,
+// and returns an error wrapping [ErrWebhookDuplicate] if either was already recorded.
+// It should be called after the signature is verified,
+// otherwise forged requests can pollute the store.
+// If the webhook then fails to be handled, call [ReplayGuard.Forget]
+// so that the redelivery of PayPal is handled rather than dropped as a duplicate.
+func (g *ReplayGuard) CheckSeen(ctx context.Context, req *VerifyWSReq, wh *Webhook) error {
+	ttl := g.TTL
+	if ttl <= 0 {
+		ttl = DefaultSeenTTL
+	}
+	var recorded []string
+	for _, key := range seenKeys(req, wh) {
+		seen, err := g.Store.Seen(ctx, key, ttl)
+		if err != nil {
+			_ = g.forget(ctx, recorded)
+			return fmt.Errorf("seen store: %w", err)
+		}
+		if seen {
+			return fmt.Errorf("%w: %s", ErrWebhookDuplicate, key)
+		}
+		recorded = append(recorded, key)
+	}
+	return nil
+}
+
+// Forget removes the transmission ID and the event ID recorded by [ReplayGuard.CheckSeen].
+func (g *ReplayGuard) Forget(ctx context.Context, req *VerifyWSReq, wh *Webhook) error {
+	return g.forget(ctx, seenKeys(req, wh))
+}
+
+func (g *ReplayGuard) forget(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := g.Store.Forget(ctx, key); err != nil {
+			return fmt.Errorf("seen store: %w", err)
+		}
+	}
+	return nil
+}
+
+func seenKeys(req *VerifyWSReq, wh *Webhook) []string {
+	return []string{"transmission:" + req.TransmissionID, "event:" + wh.ID}
+}
@@ -0,0 +1,121 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func TestMemorySeenStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewMemorySeenStore(2)
+	s.now = func() time.Time { return now }
+
+	ptesting.R(s.Seen(ctx, "a", time.Minute)).NoError(t).Equal(false)
+	ptesting.R(s.Seen(ctx, "a", time.Minute)).NoError(t).Equal(true)
+
+	now = now.Add(2 * time.Minute)
+	ptesting.R(s.Seen(ctx, "a", time.Minute)).NoError(t).Equal(false)
+	ptesting.R(s.Seen(ctx, "a", time.Minute)).NoError(t).Equal(true)
+
+	// "b" is evicted as the least recently used one
+	ptesting.R(s.Seen(ctx, "b", time.Minute)).NoError(t).Equal(false)
+	ptesting.R(s.Seen(ctx, "a", time.Minute)).NoError(t).Equal(true)
+	ptesting.R(s.Seen(ctx, "c", time.Minute)).NoError(t).Equal(false)
+	assert.Equal(t, 2, s.Len())
+	ptesting.R(s.Seen(ctx, "b", time.Minute)).NoError(t).Equal(false)
+
+	assert.NoError(t, s.Forget(ctx, "b"))
+	assert.NoError(t, s.Forget(ctx, "unknown"))
+	assert.Equal(t, 1, s.Len())
+	ptesting.R(s.Seen(ctx, "b", time.Minute)).NoError(t).Equal(false)
+}
+
+func TestReplayGuard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2016, 10, 5, 14, 54, 46, 0, time.UTC)
+	g := NewReplayGuard(nil)
+	g.Now = func() time.Time { return now }
+
+	req := &VerifyWSReq{TransmissionID: "T1", TransmissionTime: now.Add(-time.Minute)}
+	assert.NoError(t, g.CheckTime(req))
+	req.TransmissionTime = now.Add(-time.Hour)
+	assert.ErrorIs(t, g.CheckTime(req), ErrWebhookStale)
+	req.TransmissionTime = now.Add(time.Hour)
+	assert.ErrorIs(t, g.CheckTime(req), ErrWebhookStale)
+
+	wh := &Webhook{ID: "WH-1"}
+	assert.NoError(t, g.CheckSeen(ctx, req, wh))
+	assert.ErrorIs(t, g.CheckSeen(ctx, req, &Webhook{ID: "WH-2"}), ErrWebhookDuplicate)
+	// Redelivered with another transmission
+	req = &VerifyWSReq{TransmissionID: "T2"}
+	assert.ErrorIs(t, g.CheckSeen(ctx, req, wh), ErrWebhookDuplicate)
+	assert.NoError(t, g.Forget(ctx, req, wh))
+	assert.NoError(t, g.CheckSeen(ctx, req, wh))
+
+	t.Run("Handler", func(t *testing.T) {
+		h := NewWebhookHandler(WebhookVerifierFunc(
+			func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+				return true, nil
+			}), "WEBHOOK-ID")
+		h.Guard = NewReplayGuard(nil)
+		h.Guard.Now = func() time.Time { return time.Date(2016, 10, 5, 14, 55, 0, 0, time.UTC) }
+		calls := 0
+		h.HandleDefault(func(ctx context.Context, wh *Webhook) error {
+			calls++
+			return nil
+		})
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		assert.Equal(t, 1, calls)
+
+		r := newWebhookRequest(testWebhookBody)
+		r.Header.Set(HeaderTransmissionTime, "2016-10-04T14:54:46Z")
+		assert.Equal(t, http.StatusBadRequest, serveWebhook(h, r))
+	})
+
+	t.Run("Redelivery", func(t *testing.T) {
+		h := NewWebhookHandler(WebhookVerifierFunc(
+			func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+				return true, nil
+			}), "WEBHOOK-ID")
+		h.Guard = NewReplayGuard(nil)
+		h.Guard.Now = func() time.Time { return time.Date(2016, 10, 5, 14, 55, 0, 0, time.UTC) }
+		calls := 0
+		h.HandleDefault(func(ctx context.Context, wh *Webhook) error {
+			if calls++; calls == 1 {
+				return errors.New("database is down")
+			}
+			return nil
+		})
+		assert.Equal(t, http.StatusInternalServerError, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		// PayPal redelivers the failed webhook, which is handled rather than acked as a duplicate
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+		assert.Equal(t, 2, calls)
+
+		h.Store = failingStore{}
+		r := newWebhookRequest(strings.Replace(testWebhookBody, "WH-2WR", "WH-3WR", 1))
+		r.Header.Set(HeaderTransmissionID, "T-STORE")
+		assert.Equal(t, http.StatusServiceUnavailable, serveWebhook(h, r))
+		h.Store = nil
+		r = newWebhookRequest(strings.Replace(testWebhookBody, "WH-2WR", "WH-3WR", 1))
+		r.Header.Set(HeaderTransmissionID, "T-STORE")
+		assert.Equal(t, http.StatusOK, serveWebhook(h, r))
+		assert.Equal(t, 3, calls)
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Append(context.Context, *StoredWebhook) error {
+	return errors.New("disk is full")
+}
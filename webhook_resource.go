@@ -0,0 +1,105 @@
+package paypal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/adobaai/paypal/internal/webhook"
+)
+
+// Resource types of webhooks.
+const (
+	RTCheckoutOrder = webhook.RTCheckoutOrder
+	RTCapture       = webhook.RTCapture
+	RTAuthorization = webhook.RTAuthorization
+	RTRefund        = webhook.RTRefund
+	RTSale          = webhook.RTSale
+	RTSubscription  = webhook.RTSubscription
+	RTDispute       = webhook.RTDispute
+	RTInvoices      = webhook.RTInvoices
+	RTPayouts       = webhook.RTPayouts
+	RTPayoutsItem   = webhook.RTPayoutsItem
+)
+
+var (
+	resourceTypesMu sync.RWMutex
+	resourceTypes   = map[string]func() any{}
+)
+
+func init() {
+	RegisterResourceType(RTCheckoutOrder, "", func() any { return new(Order) })
+	// The v1 resources of captures, authorizations and refunds are in another shape.
+	RegisterResourceType(RTCapture, "2", func() any { return new(Capture) })
+	RegisterResourceType(RTAuthorization, "2", func() any { return new(Authorization) })
+	RegisterResourceType(RTRefund, "2", func() any { return new(Refund) })
+	RegisterResourceType(RTSale, "", func() any { return new(Sale) })
+	RegisterResourceType(RTSubscription, "", func() any { return new(Subscription) })
+	RegisterResourceType(RTDispute, "", func() any { return new(Dispute) })
+	RegisterResourceType(RTInvoices, "", func() any { return new(Invoice) })
+	RegisterResourceType(RTPayouts, "", func() any { return new(PayoutBatch) })
+	RegisterResourceType(RTPayoutsItem, "", func() any { return new(PayoutItem) })
+}
+
+// RegisterResourceType registers the function returning a new pointer
+// for decoding the resources of the type in [Webhook.Decode].
+//
+// The major version, e.g. "2" for the resource version "2.0",
+// limits the registration to the resources of the version,
+// and an empty one matches all versions without a specific registration.
+// The later registration overrides the former one.
+func RegisterResourceType(resourceType, majorVersion string, newFunc func() any) {
+	resourceTypesMu.Lock()
+	defer resourceTypesMu.Unlock()
+	resourceTypes[resourceTypeKey(resourceType, majorVersion)] = newFunc
+}
+
+func resourceTypeKey(resourceType, majorVersion string) string {
+	if majorVersion == "" {
+		return resourceType
+	}
+	return resourceType + "@" + majorVersion
+}
+
+func lookupResourceType(resourceType, version string) (f func() any, ok bool) {
+	major, _, _ := strings.Cut(version, ".")
+	resourceTypesMu.RLock()
+	defer resourceTypesMu.RUnlock()
+	if major != "" {
+		if f, ok = resourceTypes[resourceTypeKey(resourceType, major)]; ok {
+			return
+		}
+	}
+	f, ok = resourceTypes[resourceType]
+	return
+}
+
+// Decode decodes the resource according to the resource type and the resource version,
+// e.g. *[Order] for "checkout-order" and *[Capture] for "capture" of version "2.0".
+// The resource is returned as [json.RawMessage] if the type is unknown,
+// see [RegisterResourceType] to register more types.
+func (wh *Webhook) Decode() (res any, err error) {
+	raw, err := wh.RawResource()
+	if err != nil {
+		return
+	}
+	f, ok := lookupResourceType(wh.ResourceType, wh.ResourceVersion)
+	if !ok {
+		return raw, nil
+	}
+	res = f()
+	if err = json.Unmarshal(raw, res); err != nil {
+		return nil, fmt.Errorf("decode %s resource: %w", wh.ResourceType, err)
+	}
+	return
+}
+
+// ResourceAs decodes the resource of the webhook into a new T regardless of the resource type.
+func ResourceAs[T any](wh *Webhook) (res *T, err error) {
+	res = new(T)
+	if err = wh.decodeResource(res); err != nil {
+		return nil, fmt.Errorf("decode %s resource: %w", wh.ResourceType, err)
+	}
+	return
+}
@@ -0,0 +1,222 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+)
+
+const tracerName = "github.com/adobaai/paypal"
+
+// WebhookMiddleware wraps a [WebhookFunc] to add behaviors such as logging.
+type WebhookMiddleware func(next WebhookFunc) WebhookFunc
+
+// WebhookRouter dispatches webhooks to the functions registered by event type patterns.
+//
+// A pattern is a dot-separated event type, in which the segment "*" matches
+// one or more segments, e.g.:
+//
+//   - "PAYMENT.CAPTURE.COMPLETED" matches the event type only.
+//   - "PAYMENT.CAPTURE.*" matches "PAYMENT.CAPTURE.COMPLETED", "PAYMENT.CAPTURE.REFUNDED" and so on.
+//   - "BILLING.SUBSCRIPTION.*" also matches "BILLING.SUBSCRIPTION.PAYMENT.FAILED".
+//   - "*.REFUNDED" matches "PAYMENT.CAPTURE.REFUNDED" and "PAYMENT.SALE.REFUNDED".
+//   - "*" matches all event types.
+//
+// If multiple patterns match an event type, the one with more literal segments wins,
+// then the one with fewer wildcards, then the one registered earlier.
+type WebhookRouter struct {
+	mu          sync.RWMutex
+	routes      []*webhookRoute
+	middlewares []WebhookMiddleware
+}
+
+type webhookRoute struct {
+	pattern  string
+	segments []string
+	literals int
+	wildcard int
+	order    int
+	f        WebhookFunc
+}
+
+// NewWebhookRouter returns a new [WebhookRouter].
+func NewWebhookRouter() *WebhookRouter {
+	return &WebhookRouter{}
+}
+
+// Handle registers the function for the pattern,
+// replacing the function registered for the same pattern if any.
+// It panics if the pattern is invalid.
+func (r *WebhookRouter) Handle(pattern string, f WebhookFunc) {
+	if f == nil {
+		panic("paypal: nil webhook func")
+	}
+	route := &webhookRoute{pattern: pattern, segments: strings.Split(pattern, "."), f: f}
+	for _, seg := range route.segments {
+		switch {
+		case seg == "*":
+			route.wildcard++
+		case seg == "" || strings.Contains(seg, "*"):
+			panic(fmt.Sprintf("paypal: invalid webhook pattern: %q", pattern))
+		default:
+			route.literals++
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, old := range r.routes {
+		if old.pattern == pattern {
+			route.order = old.order
+			r.routes[i] = route
+			return
+		}
+	}
+	route.order = len(r.routes)
+	r.routes = append(r.routes, route)
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		a, b := r.routes[i], r.routes[j]
+		if a.literals != b.literals {
+			return a.literals > b.literals
+		}
+		if a.wildcard != b.wildcard {
+			return a.wildcard < b.wildcard
+		}
+		return a.order < b.order
+	})
+}
+
+// Use appends the middlewares, which wrap the matched function in order,
+// i.e. the first middleware is the outermost.
+// [RecoverWebhook] should be the last one so that the others see the panics as errors.
+func (r *WebhookRouter) Use(mws ...WebhookMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middlewares = append(r.middlewares, mws...)
+}
+
+// Match returns the function and the pattern matching the event type.
+func (r *WebhookRouter) Match(et EventType) (f WebhookFunc, pattern string, ok bool) {
+	segments := strings.Split(string(et), ".")
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, route := range r.routes {
+		if matchSegments(route.segments, segments) {
+			return route.f, route.pattern, true
+		}
+	}
+	return
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] != "*" {
+		return len(segments) > 0 && pattern[0] == segments[0] &&
+			matchSegments(pattern[1:], segments[1:])
+	}
+	for i := 1; i <= len(segments); i++ {
+		if matchSegments(pattern[1:], segments[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatch calls the function matching the event type of the webhook with the middlewares.
+// The webhook is ignored if there is no matching function.
+func (r *WebhookRouter) Dispatch(ctx context.Context, wh *Webhook) error {
+	f, _, ok := r.Match(wh.EventType)
+	if !ok {
+		return nil
+	}
+	r.mu.RLock()
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		f = r.middlewares[i](f)
+	}
+	r.mu.RUnlock()
+	return f(ctx, wh)
+}
+
+// Uncovered returns the known event types, see [AllEventTypes],
+// which no registered pattern matches.
+// It is useful to check the subscriptions at startup.
+func (r *WebhookRouter) Uncovered() (res []EventType) {
+	for _, et := range AllEventTypes() {
+		if _, _, ok := r.Match(et); !ok {
+			res = append(res, et)
+		}
+	}
+	return
+}
+
+// RecoverWebhook returns a middleware which recovers from panics
+// and returns them as errors, so that PayPal redelivers the webhooks later.
+func RecoverWebhook() WebhookMiddleware {
+	return func(next WebhookFunc) WebhookFunc {
+		return func(ctx context.Context, wh *Webhook) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					err = fmt.Errorf("panic: %v\n%s", p, debug.Stack())
+				}
+			}()
+			return next(ctx, wh)
+		}
+	}
+}
+
+// LogWebhook returns a middleware which logs the webhooks with the results.
+func LogWebhook(l *slog.Logger) WebhookMiddleware {
+	return func(next WebhookFunc) WebhookFunc {
+		return func(ctx context.Context, wh *Webhook) (err error) {
+			start := time.Now()
+			err = next(ctx, wh)
+			attrs := []any{
+				slog.String("id", wh.ID),
+				slog.String("event_type", string(wh.EventType)),
+				slog.String("resource_type", wh.ResourceType),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				l.ErrorContext(ctx, "PayPal webhook failed", append(attrs, slog.Any("error", err))...)
+			} else {
+				l.InfoContext(ctx, "PayPal webhook handled", attrs...)
+			}
+			return
+		}
+	}
+}
+
+// TraceWebhook returns a middleware which starts a span for each webhook
+// with the global OpenTelemetry tracer provider.
+func TraceWebhook() WebhookMiddleware {
+	tracer := otel.Tracer(tracerName)
+	return func(next WebhookFunc) WebhookFunc {
+		return func(ctx context.Context, wh *Webhook) (err error) {
+			ctx, span := tracer.Start(ctx, "PayPal webhook "+string(wh.EventType),
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					attribute.String("paypal.webhook.id", wh.ID),
+					attribute.String("paypal.webhook.event_type", string(wh.EventType)),
+					attribute.String("paypal.webhook.resource_type", wh.ResourceType),
+				))
+			defer span.End()
+			if err = next(ctx, wh); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+)
+
+func TestWebhookRouterMatch(t *testing.T) {
+	r := NewWebhookRouter()
+	nop := func(ctx context.Context, wh *Webhook) error { return nil }
+	for _, p := range []string{
+		"*",
+		"*.REFUNDED",
+		"PAYMENT.CAPTURE.*",
+		"BILLING.SUBSCRIPTION.*",
+		"PAYMENT.CAPTURE.COMPLETED",
+		"PAYMENT.*.REFUNDED",
+	} {
+		r.Handle(p, nop)
+	}
+
+	cases := []struct {
+		et      EventType
+		pattern string
+	}{
+		{PaymentCaptureCompleted, "PAYMENT.CAPTURE.COMPLETED"},
+		{PaymentCaptureDenied, "PAYMENT.CAPTURE.*"},
+		{PaymentCaptureRefunded, "PAYMENT.CAPTURE.*"}, // registered earlier
+		{PaymentSaleRefunded, "PAYMENT.*.REFUNDED"},
+		{BillingSubscriptionActivated, "BILLING.SUBSCRIPTION.*"},
+		{BillingSubscriptionPaymentFailed, "BILLING.SUBSCRIPTION.*"},
+		{CheckoutOrderApproved, "*"},
+		{"FOO.REFUNDED", "*.REFUNDED"},
+	}
+	for _, c := range cases {
+		_, pattern, ok := r.Match(c.et)
+		assert.True(t, ok, c.et)
+		assert.Equal(t, c.pattern, pattern, c.et)
+	}
+
+	r = NewWebhookRouter()
+	r.Handle("PAYMENT.CAPTURE.*", nop)
+	_, _, ok := r.Match("PAYMENT.CAPTURE")
+	assert.False(t, ok)
+	_, _, ok = r.Match(PaymentSaleCompleted)
+	assert.False(t, ok)
+
+	for _, p := range []string{"", "PAYMENT..COMPLETED", "PAYMENT.CAPTURE.COMP*"} {
+		assert.Panics(t, func() { r.Handle(p, nop) }, p)
+	}
+}
+
+func TestWebhookRouterDispatch(t *testing.T) {
+	var calls []string
+	r := NewWebhookRouter()
+	r.Handle("PAYMENT.CAPTURE.*", func(ctx context.Context, wh *Webhook) error {
+		calls = append(calls, "old")
+		return nil
+	})
+	r.Handle("PAYMENT.CAPTURE.*", func(ctx context.Context, wh *Webhook) error {
+		calls = append(calls, "capture")
+		return nil
+	})
+	r.Handle("*.REFUNDED", func(ctx context.Context, wh *Webhook) error {
+		panic("boom")
+	})
+	mw := func(name string) WebhookMiddleware {
+		return func(next WebhookFunc) WebhookFunc {
+			return func(ctx context.Context, wh *Webhook) error {
+				calls = append(calls, name)
+				return next(ctx, wh)
+			}
+		}
+	}
+	buf := new(bytes.Buffer)
+	r.Use(LogWebhook(slog.New(slog.NewTextHandler(buf, nil))), TraceWebhook(), RecoverWebhook())
+	r.Use(mw("a"), mw("b"))
+
+	ctx := context.Background()
+	require.NoError(t, r.Dispatch(ctx, &Webhook{ID: "WH-1", EventType: PaymentCaptureCompleted}))
+	assert.Equal(t, []string{"a", "b", "capture"}, calls)
+	assert.Contains(t, buf.String(), "PayPal webhook handled")
+	assert.Contains(t, buf.String(), "id=WH-1")
+
+	err := r.Dispatch(ctx, &Webhook{ID: "WH-2", EventType: PaymentSaleRefunded})
+	assert.ErrorContains(t, err, "panic: boom")
+	assert.Contains(t, buf.String(), "PayPal webhook failed")
+
+	calls = nil
+	require.NoError(t, r.Dispatch(ctx, &Webhook{ID: "WH-3", EventType: CheckoutOrderApproved}))
+	assert.Empty(t, calls)
+}
+
+func TestWebhookRouterUncovered(t *testing.T) {
+	r := NewWebhookRouter()
+	assert.Equal(t, AllEventTypes(), r.Uncovered())
+
+	nop := func(ctx context.Context, wh *Webhook) error { return nil }
+	r.Handle("PAYMENT.*", nop)
+	r.Handle("BILLING.*", nop)
+	for _, et := range r.Uncovered() {
+		assert.False(t, strings.HasPrefix(string(et), "PAYMENT."), et)
+		assert.False(t, strings.HasPrefix(string(et), "BILLING."), et)
+	}
+	assert.NotContains(t, r.Uncovered(), PaymentCaptureCompleted)
+	assert.Contains(t, r.Uncovered(), CheckoutOrderApproved)
+
+	r.Handle("*", nop)
+	assert.Empty(t, r.Uncovered())
+}
+
+func TestWebhookHandlerRouter(t *testing.T) {
+	verified := WebhookVerifierFunc(func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+		return true, nil
+	})
+	h := NewWebhookHandler(verified, "WEBHOOK-ID")
+	var got string
+	h.Router.Handle("PAYMENT.CAPTURE.*", func(ctx context.Context, wh *Webhook) error {
+		got = "wildcard"
+		return errors.New("oops")
+	})
+	h.HandleDefault(func(ctx context.Context, wh *Webhook) error {
+		got = "default"
+		return nil
+	})
+	assert.Equal(t, http.StatusInternalServerError, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+	assert.Equal(t, "wildcard", got)
+
+	h.Handle(PaymentCaptureCompleted, func(ctx context.Context, wh *Webhook) error {
+		got = "exact"
+		return nil
+	})
+	assert.Equal(t, http.StatusOK, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+	assert.Equal(t, "exact", got)
+}
@@ -0,0 +1,115 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "webhooks.jsonl")
+	s, err := OpenFileStore(name)
+	require.NoError(t, err)
+
+	verified := WebhookVerifierFunc(func(ctx context.Context, req *VerifyWSReq) (bool, error) {
+		return true, nil
+	})
+	h := NewWebhookHandler(verified, "WEBHOOK-ID")
+	h.Store = s
+	r := newWebhookRequest(testWebhookBody)
+	r.Header.Set("Authorization", "Basic c2VjcmV0")
+	assert.Equal(t, http.StatusOK, serveWebhook(h, r))
+	require.NoError(t, s.Close())
+
+	// Reopen to append
+	s, err = OpenFileStore(name)
+	require.NoError(t, err)
+	body := strings.ReplaceAll(testWebhookBody, "WH-2WR32451HC0233532-67976317FL4543714", "WH-2")
+	body = strings.ReplaceAll(body, "2014-10-23", "2014-10-25")
+	body = strings.ReplaceAll(body, "PAYMENT.CAPTURE.COMPLETED", "PAYMENT.CAPTURE.REFUNDED")
+	sw := NewStoredWebhook(http.Header{}, []byte(body), time.Now())
+	require.NoError(t, s.Append(context.Background(), sw))
+	require.NoError(t, s.Close())
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	var got []*StoredWebhook
+	require.NoError(t, ReadWebhooks(f, func(sw *StoredWebhook) error {
+		got = append(got, sw)
+		return nil
+	}))
+	require.Len(t, got, 2)
+	assert.Equal(t, testWebhookBody, got[0].Body)
+	assert.Equal(t, "103e3700-8b0c-11e6-8695-6b62a8a99ac4", got[0].Header.Get(HeaderTransmissionID))
+	assert.Empty(t, got[0].Header.Get("Authorization"))
+	assert.Equal(t, body, got[1].Body)
+
+	h.Store = webhookStoreFunc(func(ctx context.Context, sw *StoredWebhook) error {
+		return errors.New("disk full")
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, serveWebhook(h, newWebhookRequest(testWebhookBody)))
+}
+
+func TestReplayWebhooks(t *testing.T) {
+	var lines []string
+	for _, v := range []struct{ id, et, time string }{
+		{"WH-1", "PAYMENT.CAPTURE.COMPLETED", "2023-01-01T00:00:00Z"},
+		{"WH-2", "PAYMENT.CAPTURE.REFUNDED", "2023-01-02T00:00:00Z"},
+		{"WH-3", "CHECKOUT.ORDER.APPROVED", "2023-01-03T00:00:00Z"},
+		{"WH-4", "PAYMENT.CAPTURE.DENIED", "2023-01-04T00:00:00Z"},
+	} {
+		lines = append(lines, `{"received_at":"2023-01-05T00:00:00Z","header":{},"body":`+
+			`"{\"id\":\"`+v.id+`\",\"event_type\":\"`+v.et+`\",\"create_time\":\"`+v.time+`\"}"}`)
+	}
+	stream := strings.Join(lines, "\n") + "\n\n"
+
+	replay := func(filter *ReplayFilter) (ids []string, n int, err error) {
+		n, err = ReplayWebhooks(context.Background(), strings.NewReader(stream), filter,
+			func(ctx context.Context, wh *Webhook) error {
+				ids = append(ids, wh.ID)
+				if wh.ID == "WH-4" {
+					return errors.New("oops")
+				}
+				return nil
+			})
+		return
+	}
+
+	ids, n, err := replay(&ReplayFilter{
+		Patterns: []string{"PAYMENT.CAPTURE.*"},
+		Since:    time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Until:    time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"WH-2"}, ids)
+
+	ids, n, err = replay(&ReplayFilter{Patterns: []string{"CHECKOUT.*", "*.COMPLETED"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"WH-1", "WH-3"}, ids)
+
+	ids, n, err = replay(nil)
+	assert.ErrorContains(t, err, "handle PAYMENT.CAPTURE.DENIED WH-4: oops")
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"WH-1", "WH-2", "WH-3", "WH-4"}, ids)
+
+	_, err = ReplayWebhooks(context.Background(), strings.NewReader(`{"body":"{}"}`+"\nnot json\n"), nil,
+		func(ctx context.Context, wh *Webhook) error { return nil })
+	assert.ErrorContains(t, err, "line 2")
+}
+
+type webhookStoreFunc func(ctx context.Context, sw *StoredWebhook) error
+
+func (f webhookStoreFunc) Append(ctx context.Context, sw *StoredWebhook) error {
+	return f(ctx, sw)
+}
@@ -0,0 +1,156 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+func unmarshalWebhook(t *testing.T, s string) *Webhook {
+	wh := new(Webhook)
+	require.NoError(t, json.Unmarshal([]byte(s), wh))
+	return wh
+}
+
+func TestWebhookDecode(t *testing.T) {
+	t.Run("Capture", func(t *testing.T) {
+		wh := unmarshalWebhook(t, `{
+			"id": "WH-58D329510W468432D-8HN650336L201105X",
+			"event_version": "1.0",
+			"resource_type": "capture",
+			"resource_version": "2.0",
+			"event_type": "PAYMENT.CAPTURE.COMPLETED",
+			"resource": {
+				"id": "42311647XV020574X",
+				"status": "COMPLETED",
+				"amount": {"currency_code": "USD", "value": "0.48"},
+				"final_capture": true
+			}
+		}`)
+		assert.Equal(t, "42311647XV020574X", wh.Resource["id"])
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			c, ok := it.(*Capture)
+			require.True(t, ok)
+			assert.Equal(t, CaptureCompleted, c.Status)
+			assert.Equal(t, "0.48", c.Amount.Value)
+		})
+
+		// The modified resource is decoded rather than the unmarshaled one
+		wh.Resource["status"] = "DECLINED"
+		ptesting.R(ResourceAs[Capture](wh)).NoError(t).Do(func(t *testing.T, it *Capture) {
+			assert.Equal(t, CaptureDeclined, it.Status)
+		})
+		wh.Resource = map[string]any{"id": "5O190127TN364715T", "status": "DECLINED"}
+		ptesting.R(ResourceAs[Capture](wh)).NoError(t).Do(func(t *testing.T, it *Capture) {
+			assert.Equal(t, "5O190127TN364715T", it.ID)
+			assert.Equal(t, CaptureDeclined, it.Status)
+		})
+
+		// The v1 capture is in another shape
+		wh.ResourceVersion = "1.0"
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			assert.IsType(t, json.RawMessage{}, it)
+		})
+	})
+
+	t.Run("Sale", func(t *testing.T) {
+		var v struct {
+			Sale json.RawMessage `json:"sale"`
+		}
+		bs := ptesting.R(os.ReadFile("resource.json")).NoError(t).V()
+		require.NoError(t, json.Unmarshal(bs, &v))
+		wh := &Webhook{ResourceType: RTSale, EventType: PaymentSaleRefunded}
+		require.NoError(t, json.Unmarshal(v.Sale, &wh.Resource))
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			sale := it.(*Sale)
+			assert.Equal(t, "84A08461610205721", sale.ID)
+			assert.Equal(t, "I-HAD0FP0F5NG3", sale.BillingAgreementId)
+			assert.Equal(t, "0.40", sale.Amount.Total)
+		})
+	})
+
+	t.Run("Subscription", func(t *testing.T) {
+		wh := unmarshalWebhook(t, `{
+			"resource_type": "subscription",
+			"resource_version": "2.0",
+			"event_type": "BILLING.SUBSCRIPTION.ACTIVATED",
+			"resource": {"id": "I-BW452GLLEP1G", "status": "ACTIVE", "plan_id": "P-5ML4271244454362WXNWU5NQ"}
+		}`)
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			assert.Equal(t, &Subscription{
+				ID:     "I-BW452GLLEP1G",
+				Status: SSActive,
+				PlanID: "P-5ML4271244454362WXNWU5NQ",
+			}, it)
+		})
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		wh := unmarshalWebhook(t, `{
+			"resource_type": "merchant-onboarding",
+			"event_type": "MERCHANT.ONBOARDING.COMPLETED",
+			"resource": {"partner_client_id": "AZ", "merchant_id": "M1"}
+		}`)
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			assert.JSONEq(t, `{"partner_client_id": "AZ", "merchant_id": "M1"}`,
+				string(it.(json.RawMessage)))
+		})
+
+		type onboarding struct {
+			MerchantID string `json:"merchant_id"`
+		}
+		ptesting.R(ResourceAs[onboarding](wh)).NoError(t).Equal(&onboarding{MerchantID: "M1"})
+	})
+
+	t.Run("Constructed", func(t *testing.T) {
+		wh := &Webhook{
+			ResourceType: RTDispute,
+			Resource:     map[string]any{"dispute_id": "PP-D-4012", "status": "OPEN"},
+		}
+		ptesting.R(wh.Decode()).NoError(t).Do(func(t *testing.T, it any) {
+			assert.Equal(t, &Dispute{DisputeID: "PP-D-4012", Status: DSOpen}, it)
+		})
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		wh := unmarshalWebhook(t, `{"resource_type": "invoices", "resource": {"id": 1}}`)
+		ptesting.R(wh.Decode()).ErrorContains(t, "decode invoices resource")
+	})
+}
+
+func TestSimulateWebhookEvent(t *testing.T) {
+	ctx := context.Background()
+	h := ServeJSON(t, http.MethodPost, "/v1/notifications/simulate-event", 202, `{
+		"id": "WH-SIM-1",
+		"event_version": "1.0",
+		"resource_type": "capture",
+		"resource_version": "2.0",
+		"event_type": "PAYMENT.CAPTURE.COMPLETED",
+		"resource": {"id": "42311647XV020574X", "status": "COMPLETED"}
+	}`)
+	c := NewMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := ptesting.R(io.ReadAll(r.Body)).NoError(t).V()
+		assert.JSONEq(t, `{
+			"webhook_id": "0EH40505U7160970P",
+			"event_type": "PAYMENT.CAPTURE.COMPLETED",
+			"resource_version": "2.0"
+		}`, string(body))
+		h.ServeHTTP(w, r)
+	}))
+	wh := ptesting.R(c.SimulateWebhookEvent(ctx, &SimulateWebhookEventReq{
+		WebhookID:       "0EH40505U7160970P",
+		EventType:       PaymentCaptureCompleted,
+		ResourceVersion: "2.0",
+	})).NoError(t).V()
+	assert.Equal(t, "WH-SIM-1", wh.ID)
+	capture := ptesting.R(wh.Decode()).NoError(t).V().(*Capture)
+	assert.Equal(t, "42311647XV020574X", capture.ID)
+}
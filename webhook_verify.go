@@ -0,0 +1,262 @@
+package paypal
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobaai/paypal/internal/webhook"
+)
+
+// ErrInvalidWebhookSign indicates the webhook signature is invalid.
+var ErrInvalidWebhookSign = errors.New("invalid webhook signature")
+
+// DefaultCertCacheTTL is the default duration to cache the certificates.
+const DefaultCertCacheTTL = 24 * time.Hour
+
+// CertVerifier is a [WebhookVerifier] which verifies the webhook signatures locally
+// with the certificates downloaded from PayPal,
+// so that no verification API call is needed for each webhook.
+//
+// The signed string is "<transmission_id>|<transmission_time>|<webhook_id>|<crc32>",
+// where crc32 is the decimal CRC32 checksum of the raw request body,
+// and the signature is verified with SHA256withRSA against the certificate from the cert URL.
+//
+// See https://developer.paypal.com/api/rest/webhooks/rest/#link-selfverificationmethod.
+type CertVerifier struct {
+	// HTTPClient is used to download the certificates, [http.DefaultClient] is used if nil.
+	HTTPClient *http.Client
+	// Roots is the root certificates to validate the certificate chain,
+	// the system root certificates are used if nil.
+	Roots *x509.CertPool
+	// AllowedHosts is the hosts allowed for the cert URL and the certificate names.
+	// A host starting with "." matches all subdomains.
+	// The default is [".paypal.com"].
+	AllowedHosts []string
+	// CacheTTL is the duration to cache the certificates by URL,
+	// [DefaultCertCacheTTL] is used if zero.
+	// The certificates are never cached beyond their expiration time.
+	CacheTTL time.Duration
+	// Now returns the current time, [time.Now] is used if nil.
+	Now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*certEntry
+}
+
+type certEntry struct {
+	cert      *x509.Certificate
+	expiresAt time.Time
+}
+
+// NewCertVerifier returns a new [CertVerifier] with the default settings.
+func NewCertVerifier() *CertVerifier {
+	return &CertVerifier{}
+}
+
+func (v *CertVerifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+func (v *CertVerifier) allowed(host string) bool {
+	hosts := v.AllowedHosts
+	if hosts == nil {
+		hosts = []string{".paypal.com"}
+	}
+	host = strings.ToLower(host)
+	for _, h := range hosts {
+		if host == h || strings.HasPrefix(h, ".") && strings.HasSuffix(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyWebhookSign verifies the webhook signature locally.
+// The WebhookEvent of the request must be the raw request body
+// as [json.RawMessage], []byte or string, see [NewVerifyWSReq].
+//
+// An error wrapping [ErrInvalidWebhookSign] is returned if the signature is invalid,
+// other errors indicate the verification could not complete, e.g. download failures.
+func (v *CertVerifier) VerifyWebhookSign(ctx context.Context, req *VerifyWSReq,
+) (ok bool, err error) {
+	body, err := rawWebhookEvent(req)
+	if err != nil {
+		return
+	}
+	if req.AuthAlgo != "SHA256withRSA" {
+		return false, fmt.Errorf("%w: unsupported auth algo: %q", ErrInvalidWebhookSign, req.AuthAlgo)
+	}
+	sig, err := base64.StdEncoding.DecodeString(req.TransmissionSig)
+	if err != nil {
+		return false, fmt.Errorf("%w: decode signature: %v", ErrInvalidWebhookSign, err)
+	}
+
+	cert, err := v.certificate(ctx, req.CertURL)
+	if err != nil {
+		return
+	}
+	pub, isRSA := cert.PublicKey.(*rsa.PublicKey)
+	if !isRSA {
+		return false, fmt.Errorf("%w: not an RSA certificate", ErrInvalidWebhookSign)
+	}
+
+	hashed := sha256.Sum256([]byte(SignedString(req, body)))
+	if err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], sig); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidWebhookSign, err)
+	}
+	return true, nil
+}
+
+// rawWebhookEvent returns the WebhookEvent of the request, which must be the raw body.
+func rawWebhookEvent(req *VerifyWSReq) ([]byte, error) {
+	switch ev := req.WebhookEvent.(type) {
+	case json.RawMessage:
+		return ev, nil
+	case []byte:
+		return ev, nil
+	case string:
+		return []byte(ev), nil
+	default:
+		return nil, fmt.Errorf("webhook event must be the raw body, got %T", ev)
+	}
+}
+
+// SignedString returns the string signed by PayPal for the webhook with the raw body.
+// The transmission time is the raw header as is, since it may not round-trip through [time.Time],
+// e.g. "2016-10-05T14:54:46+00:00"; TransmissionTime is formatted only if the raw one is empty.
+func SignedString(req *VerifyWSReq, body []byte) string {
+	tt := req.RawTransmissionTime
+	if tt == "" {
+		tt = req.TransmissionTime.Format(time.RFC3339Nano)
+	}
+	return webhook.SignedString(req.TransmissionID, tt, req.WebhookID, body)
+}
+
+// certificate returns the validated certificate of the URL from the cache or PayPal.
+func (v *CertVerifier) certificate(ctx context.Context, certURL string,
+) (res *x509.Certificate, err error) {
+	u, err := url.Parse(certURL)
+	if err != nil || u.Scheme != "https" || !v.allowed(u.Hostname()) {
+		return nil, fmt.Errorf("%w: cert URL not allowed: %q", ErrInvalidWebhookSign, certURL)
+	}
+
+	now := v.now()
+	v.mu.Lock()
+	e, ok := v.cache[certURL]
+	v.mu.Unlock()
+	if ok && now.Before(e.expiresAt) {
+		return e.cert, nil
+	}
+
+	chain, err := v.download(ctx, certURL)
+	if err != nil {
+		return
+	}
+	if res, err = v.validate(chain, now); err != nil {
+		return
+	}
+
+	ttl := v.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultCertCacheTTL
+	}
+	e = &certEntry{cert: res, expiresAt: now.Add(ttl)}
+	if res.NotAfter.Before(e.expiresAt) {
+		e.expiresAt = res.NotAfter
+	}
+	v.mu.Lock()
+	if v.cache == nil {
+		v.cache = map[string]*certEntry{}
+	}
+	v.cache[certURL] = e
+	v.mu.Unlock()
+	return
+}
+
+func (v *CertVerifier) download(ctx context.Context, certURL string,
+) (res []*x509.Certificate, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	hc := v.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	hres, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download cert: %w", err)
+	}
+	defer hres.Body.Close()
+	if hres.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download cert: status %d", hres.StatusCode)
+	}
+	bs, err := io.ReadAll(io.LimitReader(hres.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("download cert: %w", err)
+	}
+
+	for {
+		var block *pem.Block
+		block, bs = pem.Decode(bs)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: parse cert: %v", ErrInvalidWebhookSign, err)
+		}
+		res = append(res, cert)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%w: no certificate found", ErrInvalidWebhookSign)
+	}
+	return
+}
+
+// validate validates the chain, in which the first one is the leaf certificate.
+func (v *CertVerifier) validate(chain []*x509.Certificate, now time.Time,
+) (res *x509.Certificate, err error) {
+	res = chain[0]
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = res.Verify(x509.VerifyOptions{
+		Roots:         v.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: verify cert: %v", ErrInvalidWebhookSign, err)
+	}
+
+	names := append([]string{res.Subject.CommonName}, res.DNSNames...)
+	for _, name := range names {
+		if v.allowed(name) {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: cert name not allowed: %v", ErrInvalidWebhookSign, names)
+}
@@ -0,0 +1,145 @@
+package paypal
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adobaai/paypal/ptesting"
+)
+
+// countingTransport counts the requests, e.g. the certificate downloads.
+type countingTransport struct {
+	next  http.RoundTripper
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.calls++
+	return t.next.RoundTrip(r)
+}
+
+func TestCertVerifier(t *testing.T) {
+	ctx := context.Background()
+	signer := ptesting.NewWebhookSigner("1JE4291016473214C")
+	downloads := &countingTransport{next: signer}
+	now := time.Now()
+	v := &CertVerifier{
+		HTTPClient: &http.Client{Transport: downloads},
+		Roots:      signer.Roots(),
+		CacheTTL:   time.Hour,
+		Now:        func() time.Time { return now },
+	}
+	// 1636890356 is the CRC32 of testWebhookBody
+	sig := ptesting.R(signer.SignString(
+		"103e3700-8b0c-11e6-8695-6b62a8a99ac4|2016-10-05T14:54:46Z|1JE4291016473214C|1636890356",
+	)).NoError(t).V()
+	newReq := func() *VerifyWSReq {
+		return &VerifyWSReq{
+			AuthAlgo:         "SHA256withRSA",
+			CertURL:          ptesting.TestCertURL,
+			TransmissionID:   "103e3700-8b0c-11e6-8695-6b62a8a99ac4",
+			TransmissionTime: time.Date(2016, 10, 5, 14, 54, 46, 0, time.UTC),
+			TransmissionSig:  sig,
+			WebhookID:        "1JE4291016473214C",
+			WebhookEvent:     json.RawMessage(testWebhookBody),
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		ptesting.R(v.VerifyWebhookSign(ctx, newReq())).NoError(t).Equal(true)
+		ptesting.R(v.VerifyWebhookSign(ctx, newReq())).NoError(t).Equal(true)
+		assert.Equal(t, 1, downloads.calls)
+
+		now = now.Add(2 * time.Hour)
+		ptesting.R(v.VerifyWebhookSign(ctx, newReq())).NoError(t).Equal(true)
+		assert.Equal(t, 2, downloads.calls)
+	})
+
+	t.Run("RawTime", func(t *testing.T) {
+		// The header is signed as is, even if it is not in the canonical RFC 3339 form
+		tt := "2016-10-05T14:54:46.120+00:00"
+		r := newWebhookRequest(testWebhookBody)
+		r.Header.Set(HeaderCertURL, newReq().CertURL)
+		r.Header.Set(HeaderTransmissionTime, tt)
+		r.Header.Set(HeaderTransmissionSig, ptesting.R(signer.SignString(
+			"103e3700-8b0c-11e6-8695-6b62a8a99ac4|"+tt+"|1JE4291016473214C|1636890356",
+		)).NoError(t).V())
+		req := ptesting.R(NewVerifyWSReq(r.Header, []byte(testWebhookBody), "1JE4291016473214C")).
+			NoError(t).V()
+		assert.Equal(t, time.Date(2016, 10, 5, 14, 54, 46, 120e6, time.UTC), req.TransmissionTime.UTC())
+		ptesting.R(v.VerifyWebhookSign(ctx, req)).NoError(t).Equal(true)
+
+		req.RawTransmissionTime = ""
+		ptesting.R(v.VerifyWebhookSign(ctx, req)).ErrorIs(t, ErrInvalidWebhookSign)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		req := newReq()
+		req.WebhookEvent = json.RawMessage(`{"id":"WH-FAKE"}`)
+		ptesting.R(v.VerifyWebhookSign(ctx, req)).ErrorIs(t, ErrInvalidWebhookSign)
+
+		req = newReq()
+		req.WebhookID = "ANOTHER"
+		ptesting.R(v.VerifyWebhookSign(ctx, req)).ErrorIs(t, ErrInvalidWebhookSign)
+
+		req = newReq()
+		req.AuthAlgo = "SHA1withRSA"
+		ptesting.R(v.VerifyWebhookSign(ctx, req)).ErrorIs(t, ErrInvalidWebhookSign)
+	})
+
+	t.Run("CertURL", func(t *testing.T) {
+		for _, u := range []string{
+			"https://evil.example.com/cert.pem",
+			"https://api.paypal.com.evil.com/cert.pem",
+			"http://api.paypal.com/v1/notifications/certs/CERT",
+		} {
+			req := newReq()
+			req.CertURL = u
+			ptesting.R(v.VerifyWebhookSign(ctx, req)).ErrorIs(t, ErrInvalidWebhookSign, u)
+		}
+	})
+
+	t.Run("UntrustedChain", func(t *testing.T) {
+		v := &CertVerifier{
+			HTTPClient: signer.HTTPClient(),
+			Roots:      x509.NewCertPool(),
+		}
+		ptesting.R(v.VerifyWebhookSign(ctx, newReq())).ErrorIs(t, ErrInvalidWebhookSign)
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		h := NewWebhookHandler(v, "1JE4291016473214C")
+		req := newReq()
+		r := newWebhookRequest(testWebhookBody)
+		r.Header.Set(HeaderCertURL, req.CertURL)
+		r.Header.Set(HeaderTransmissionSig, req.TransmissionSig)
+		assert.Equal(t, http.StatusOK, serveWebhook(h, r))
+
+		r = newWebhookRequest(testWebhookBody)
+		r.Header.Set(HeaderCertURL, req.CertURL)
+		assert.Equal(t, http.StatusUnauthorized, serveWebhook(h, r))
+	})
+}
+
+func TestSignedString(t *testing.T) {
+	req := &VerifyWSReq{
+		TransmissionID:   "69cd13f0-d67a-11e5-baa3-778b53f4ae55",
+		TransmissionTime: time.Date(2016, 2, 18, 20, 1, 35, 0, time.UTC),
+		WebhookID:        "1JE4291016473214C",
+	}
+	require.Equal(t,
+		"69cd13f0-d67a-11e5-baa3-778b53f4ae55|2016-02-18T20:01:35Z|1JE4291016473214C|838965618",
+		SignedString(req, []byte(`{"id":"WH-1"}`)))
+
+	req.RawTransmissionTime = "2016-02-18T20:01:35+00:00"
+	require.Equal(t,
+		"69cd13f0-d67a-11e5-baa3-778b53f4ae55|2016-02-18T20:01:35+00:00|1JE4291016473214C|838965618",
+		SignedString(req, []byte(`{"id":"WH-1"}`)))
+}